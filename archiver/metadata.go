@@ -3,12 +3,42 @@ package archiver
 import (
 	"fmt"
 	"net/url"
+	"path"
 	"slices"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/xremming/rulesraker/parser"
 )
 
+// documentDir returns the directory the files of the document are archived in
+// relative to the archive directory. The comprehensive rules are archived
+// directly in a directory per format.
+func documentDir(document parser.DocumentKind) string {
+	if document == "" || document == parser.CR {
+		return ""
+	}
+
+	return strings.ToLower(string(document))
+}
+
+// DocumentPath returns the path of the archived file of the document relative
+// to the archive directory.
+func DocumentPath(document parser.DocumentKind, date time.Time, format string) string {
+	return path.Join(documentDir(document), format, fmt.Sprintf("%s.%s", date.Format("2006-01-02"), format))
+}
+
+// documentOrDefault returns the document, documents are the comprehensive
+// rules when left out from the metadata.
+func documentOrDefault(document parser.DocumentKind) parser.DocumentKind {
+	if document == "" {
+		return parser.CR
+	}
+
+	return document
+}
+
 type MissingDate struct {
 	Date    JSONDate
 	Source  string
@@ -21,19 +51,25 @@ type OneOffURL struct {
 	Files     map[string]string
 }
 
+// URLFormats are the templates of the URLs the documents might be found at.
+// Available are the templates of the comprehensive rules and Documents the
+// templates of the other documents. Templates without {{ext}} are checked
+// only for the format of their extension.
 type URLFormats struct {
 	Available []string
+	Documents map[parser.DocumentKind][]string `json:",omitempty"`
 	OneOff    []OneOffURL
 }
 
 type PossibleURL struct {
+	Document  parser.DocumentKind
 	Date      time.Time
 	Format    string
 	URL       url.URL
 	Available bool
 }
 
-func (u URLFormats) PossibleURLs(date time.Time) []PossibleURL {
+func expandURLTemplates(document parser.DocumentKind, date time.Time, urlTemplates []string) []PossibleURL {
 	var out []PossibleURL
 
 	for _, ext := range []string{"txt", "pdf", "docx"} {
@@ -41,11 +77,17 @@ func (u URLFormats) PossibleURLs(date time.Time) []PossibleURL {
 			"{{year}}", date.Format("2006"),
 			"{{yearShort}}", date.Format("06"),
 			"{{month}}", date.Format("01"),
+			"{{monthName}}", date.Format("Jan"),
 			"{{day}}", date.Format("02"),
+			"{{dayShort}}", date.Format("2"),
 			"{{ext}}", ext,
 		)
 
-		for _, urlTemplate := range u.Available {
+		for _, urlTemplate := range urlTemplates {
+			if !strings.Contains(urlTemplate, "{{ext}}") && path.Ext(urlTemplate) != "."+ext {
+				continue
+			}
+
 			replaced := varReplacer.Replace(urlTemplate)
 			url, err := url.Parse(replaced)
 			if err != nil {
@@ -53,6 +95,7 @@ func (u URLFormats) PossibleURLs(date time.Time) []PossibleURL {
 			}
 
 			out = append(out, PossibleURL{
+				Document:  document,
 				Date:      date,
 				Format:    ext,
 				URL:       *url,
@@ -61,6 +104,16 @@ func (u URLFormats) PossibleURLs(date time.Time) []PossibleURL {
 		}
 	}
 
+	return out
+}
+
+func (u URLFormats) PossibleURLs(date time.Time) []PossibleURL {
+	out := expandURLTemplates(parser.CR, date, u.Available)
+
+	for _, document := range parser.Documents {
+		out = append(out, expandURLTemplates(document, date, u.Documents[document])...)
+	}
+
 	for _, oneOff := range u.OneOff {
 		if time.Time(oneOff.Date).Equal(date) {
 			for ext, oneOffURL := range oneOff.Files {
@@ -70,6 +123,7 @@ func (u URLFormats) PossibleURLs(date time.Time) []PossibleURL {
 				}
 
 				out = append(out, PossibleURL{
+					Document:  parser.CR,
 					Date:      date,
 					Format:    ext,
 					URL:       *parsedURL,
@@ -93,6 +147,7 @@ type ResponseMetadata struct {
 // found through other means. Such a file might have come from e.g. the
 // https://github.com/pit142857/mtg-cr repository or the Internet Archive.
 type FoundFile struct {
+	Document    parser.DocumentKind `json:",omitempty"`
 	Date        JSONDate
 	Format      string
 	File        string
@@ -101,11 +156,14 @@ type FoundFile struct {
 	Comment     string
 }
 
+// Rule is an archived file of a document, Document is left out for the
+// comprehensive rules.
 type Rule struct {
-	Date   JSONDate
-	Format string
-	File   string
-	URL    *string
+	Document parser.DocumentKind `json:",omitempty"`
+	Date     JSONDate
+	Format   string
+	File     string
+	URL      *string
 
 	ResponseMetadata *ResponseMetadata `json:",omitempty"`
 }
@@ -119,26 +177,40 @@ type Metadata struct {
 	Rules              []Rule
 }
 
+// fileKey returns the key files are sorted and deduplicated by, the files of
+// the comprehensive rules are sorted first.
+func fileKey(document parser.DocumentKind, date JSONDate, format string) string {
+	key := fmt.Sprintf("%s_%s", date.String(), format)
+	if documentOrDefault(document) != parser.CR {
+		key = fmt.Sprintf("%s_%s", document, key)
+	}
+
+	return key
+}
+
 func (m *Metadata) PrepareForEncoding() {
 	// sort found files
 	sort.Slice(m.FoundFiles, func(i, j int) bool {
-		keyI := fmt.Sprintf("%s_%s", m.FoundFiles[i].Date.String(), m.FoundFiles[i].Format)
-		keyJ := fmt.Sprintf("%s_%s", m.FoundFiles[j].Date.String(), m.FoundFiles[j].Format)
+		keyI := fileKey(m.FoundFiles[i].Document, m.FoundFiles[i].Date, m.FoundFiles[i].Format)
+		keyJ := fileKey(m.FoundFiles[j].Document, m.FoundFiles[j].Date, m.FoundFiles[j].Format)
 		return keyI < keyJ
 	})
 
 	// add all found files to rules
 	for _, foundFile := range m.FoundFiles {
 		m.Rules = append(m.Rules, Rule{
-			Date:   foundFile.Date,
-			Format: foundFile.Format,
-			File:   foundFile.File,
+			Document: foundFile.Document,
+			Date:     foundFile.Date,
+			Format:   foundFile.Format,
+			File:     foundFile.File,
 		})
 	}
 
-	// add all dates from rules to known existing dates
+	// add all dates from the comprehensive rules to known existing dates
 	for _, rule := range m.Rules {
-		m.KnownExistingDates = append(m.KnownExistingDates, rule.Date)
+		if documentOrDefault(rule.Document) == parser.CR {
+			m.KnownExistingDates = append(m.KnownExistingDates, rule.Date)
+		}
 	}
 
 	// remove duplicate known existing dates
@@ -173,7 +245,7 @@ func (m *Metadata) PrepareForEncoding() {
 	slices.Reverse(m.Rules)
 
 	for _, rule := range m.Rules {
-		key := fmt.Sprintf("%s-%s", rule.Date.String(), rule.Format)
+		key := fileKey(rule.Document, rule.Date, rule.Format)
 
 		if _, ok := seenRules[key]; !ok {
 			seenRules[key] = struct{}{}
@@ -185,9 +257,27 @@ func (m *Metadata) PrepareForEncoding() {
 
 	// sort rules
 	sort.Slice(m.Rules, func(i, j int) bool {
-		keyI := fmt.Sprintf("%s_%s", m.Rules[i].Date.String(), m.Rules[i].Format)
-		keyJ := fmt.Sprintf("%s_%s", m.Rules[j].Date.String(), m.Rules[j].Format)
+		keyI := fileKey(m.Rules[i].Document, m.Rules[i].Date, m.Rules[i].Format)
+		keyJ := fileKey(m.Rules[j].Document, m.Rules[j].Date, m.Rules[j].Format)
 
 		return keyI < keyJ
 	})
 }
+
+// RuleFile returns the archived comprehensive rules file of the given format
+// for the date.
+func (m Metadata) RuleFile(date JSONDate, format string) (Rule, bool) {
+	return m.DocumentFile(parser.CR, date, format)
+}
+
+// DocumentFile returns the archived file of the document of the given format
+// for the date.
+func (m Metadata) DocumentFile(document parser.DocumentKind, date JSONDate, format string) (Rule, bool) {
+	for _, rule := range m.Rules {
+		if documentOrDefault(rule.Document) == document && rule.Date.String() == date.String() && rule.Format == format {
+			return rule, true
+		}
+	}
+
+	return Rule{}, false
+}
@@ -0,0 +1,151 @@
+package archiver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/xremming/rulesraker/parser"
+)
+
+// parseableFormats are the formats the parser can read, in order of
+// preference. Only the text files can be parsed, releases archived only as
+// .docx or .pdf are resolved but not parsed.
+var parseableFormats = []string{"txt"}
+
+// Resolution is the release of the comprehensive rules in effect on a date.
+type Resolution struct {
+	// Release is the date of the release in effect.
+	Release JSONDate
+	// Missing is set when the release is known to be missing from the archive.
+	Missing *MissingDate
+	// Formats are the formats the release is archived in.
+	Formats []string
+	// File is the archived file the rules were parsed from.
+	File string
+	// Rules are the parsed rules, nil when the release could not be parsed.
+	Rules *parser.Rules
+	// Err is the reason the release could not be parsed.
+	Err error
+}
+
+// releases returns the dates of all known releases of the comprehensive rules
+// in order, including the ones missing from the archive.
+func (m Metadata) releases() []JSONDate {
+	dates := slices.Clone(m.KnownExistingDates)
+	for _, missing := range m.KnownMissingDates {
+		dates = append(dates, missing.Date)
+	}
+
+	slices.SortFunc(dates, func(a, b JSONDate) int {
+		return time.Time(a).Compare(time.Time(b))
+	})
+
+	return slices.CompactFunc(dates, func(a, b JSONDate) bool {
+		return a.String() == b.String()
+	})
+}
+
+// missingDate returns the known missing date of the release if it is missing.
+func (m Metadata) missingDate(release JSONDate) *MissingDate {
+	for _, missing := range m.KnownMissingDates {
+		if missing.Date.String() == release.String() {
+			return &missing
+		}
+	}
+
+	return nil
+}
+
+// formats returns the formats the release is archived in.
+func (m Metadata) formats(release JSONDate) []string {
+	var out []string
+	for _, rule := range m.Rules {
+		if _, ok := m.RuleFile(release, rule.Format); ok && !slices.Contains(out, rule.Format) {
+			out = append(out, rule.Format)
+		}
+	}
+
+	return out
+}
+
+// ReleaseOn returns the latest release of the comprehensive rules published on
+// or before the date.
+func (m Metadata) ReleaseOn(date time.Time) (JSONDate, bool) {
+	releases := m.releases()
+	for i := len(releases) - 1; i >= 0; i-- {
+		if !time.Time(releases[i]).After(date) {
+			return releases[i], true
+		}
+	}
+
+	return JSONDate{}, false
+}
+
+// resolve parses the release using the best parseable format it is archived
+// in.
+func (m Metadata) resolve(archiveDir string, release JSONDate, opts parser.ParseOptions) Resolution {
+	res := Resolution{
+		Release: release,
+		Missing: m.missingDate(release),
+		Formats: m.formats(release),
+	}
+
+	if res.Missing != nil {
+		return res
+	}
+
+	for _, format := range parseableFormats {
+		file, ok := m.RuleFile(release, format)
+		if !ok {
+			continue
+		}
+
+		res.File = file.File
+		res.Rules, res.Err = parseFile(filepath.Join(archiveDir, file.File), opts)
+		return res
+	}
+
+	res.Err = fmt.Errorf("only .txt files can be parsed but the release is archived as %v", res.Formats)
+	return res
+}
+
+func parseFile(name string, opts parser.ParseOptions) (*parser.Rules, error) {
+	fp, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	rules, _, err := parser.ParseWithOptions(fp, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+// RulesOn resolves the release of the comprehensive rules in effect on the
+// date and parses it from the archive directory. Releases are archived by the
+// date they were published, so while the parsed rules take effect only after
+// the date the previous release is used instead. Only releases archived as
+// .txt are parsed, for other releases Err lists the formats they are archived
+// in.
+func (m Metadata) RulesOn(archiveDir string, date time.Time, opts parser.ParseOptions) (Resolution, error) {
+	before := date
+	for {
+		release, ok := m.ReleaseOn(before)
+		if !ok {
+			return Resolution{}, fmt.Errorf("no known release of the rules in effect on %s", date.Format("2006-01-02"))
+		}
+
+		res := m.resolve(archiveDir, release, opts)
+		if res.Rules == nil || !res.Rules.EffectiveDate.After(date) {
+			return res, nil
+		}
+
+		before = time.Time(release).AddDate(0, 0, -1)
+	}
+}
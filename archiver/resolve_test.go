@@ -0,0 +1,146 @@
+package archiver
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xremming/rulesraker/parser"
+)
+
+// testRules is the smallest rules file which parses, EFFECTIVE is replaced
+// with the effective date.
+const testRules = `Magic: The Gathering Comprehensive Rules
+
+These rules are effective as of EFFECTIVE.
+
+Contents
+
+1. Game Concepts
+100. General
+
+Glossary
+
+Credits
+
+1. Game Concepts
+
+100. General
+
+100.1. These Magic rules apply to any Magic game with two or more players.
+
+Glossary
+
+Ability
+Text on an object that explains what that object does.
+
+Credits
+
+Magic: The Gathering Original Game Design: Richard Garfield
+`
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// testMetadata writes the txt files of the releases to a new archive directory
+// and returns the directory and metadata of it. Releases are mapped to their
+// effective dates, releases without one are archived only as a pdf.
+func testMetadata(t *testing.T, releases map[string]string, missing []string) (string, Metadata) {
+	dir := t.TempDir()
+
+	var m Metadata
+	for release, effective := range releases {
+		m.KnownExistingDates = append(m.KnownExistingDates, JSONDate(date(release)))
+
+		format := "txt"
+		if effective == "" {
+			format = "pdf"
+		}
+
+		file := path.Join(format, release+"."+format)
+		m.Rules = append(m.Rules, Rule{Date: JSONDate(date(release)), Format: format, File: file})
+
+		text := ""
+		if effective != "" {
+			text = strings.Replace(testRules, "EFFECTIVE", date(effective).Format("January 2, 2006"), 1)
+		}
+
+		err := os.MkdirAll(filepath.Join(dir, format), 0o755)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(dir, file), []byte(text), 0o644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, release := range missing {
+		m.KnownMissingDates = append(m.KnownMissingDates, MissingDate{Date: JSONDate(date(release)), Source: "test"})
+	}
+
+	return dir, m
+}
+
+func TestRulesOn(t *testing.T) {
+	dir, m := testMetadata(t, map[string]string{
+		"2024-01-01": "2024-01-10",
+		"2024-02-01": "",
+		"2024-03-01": "2024-03-01",
+		"2024-04-01": "2024-05-05",
+		"2024-05-01": "2024-05-25",
+	}, []string{"2024-02-15"})
+
+	tests := map[string]struct {
+		release string
+		formats []string
+		missing bool
+		parsed  bool
+	}{
+		"2023-12-20": {},
+		"2024-01-05": {},
+		"2024-01-20": {release: "2024-01-01", formats: []string{"txt"}, parsed: true},
+		"2024-02-10": {release: "2024-02-01", formats: []string{"pdf"}},
+		"2024-02-20": {release: "2024-02-15", missing: true},
+		"2024-04-10": {release: "2024-03-01", formats: []string{"txt"}, parsed: true},
+		"2024-05-03": {release: "2024-03-01", formats: []string{"txt"}, parsed: true},
+		"2024-05-30": {release: "2024-05-01", formats: []string{"txt"}, parsed: true},
+	}
+
+	for on, test := range tests {
+		res, err := m.RulesOn(dir, date(on), parser.ParseOptions{})
+		if test.release == "" {
+			if err == nil {
+				t.Errorf("RulesOn(%s) = %s, expected an error", on, res.Release)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("RulesOn(%s) returned %v", on, err)
+			continue
+		}
+
+		if res.Release.String() != test.release || !slices.Equal(res.Formats, test.formats) || (res.Missing != nil) != test.missing {
+			t.Errorf("RulesOn(%s) = release %s in %v, missing %v, expected release %s in %v, missing %v",
+				on, res.Release, res.Formats, res.Missing != nil, test.release, test.formats, test.missing)
+		}
+
+		// Missing releases are not parsed but are not errors either.
+		if (res.Rules != nil) != test.parsed || (res.Err == nil) != (test.parsed || test.missing) {
+			t.Errorf("RulesOn(%s) parsed %v with %v, expected parsed %v", on, res.Rules != nil, res.Err, test.parsed)
+		}
+
+		if res.Rules != nil && res.Rules.EffectiveDate.After(date(on)) {
+			t.Errorf("RulesOn(%s) returned rules effective %s", on, res.Rules.EffectiveDate)
+		}
+	}
+}
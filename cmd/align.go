@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+var (
+	alignLanguage string
+	alignPairs    bool
+)
+
+func openAndParseTranslation(cmd *cobra.Command, path string) (parser.Rules, error) {
+	cmd.Printf("opening translated rules %s\n", path)
+	fp, err := os.Open(path)
+	if err != nil {
+		return parser.Rules{}, err
+	}
+	defer fp.Close()
+
+	cmd.Println("parsing translated rules")
+	opts := parser.ParseOptions{Lenient: lenient, Language: parser.Language(alignLanguage)}
+	rules, diagnostics, err := parser.ParseWithOptions(fp, opts)
+	for _, d := range diagnostics {
+		cmd.Printf("%s:%v\n", path, d)
+	}
+	if err != nil {
+		return parser.Rules{}, err
+	}
+
+	return rules, nil
+}
+
+func sectionText(section *parser.Section) string {
+	if section == nil {
+		return ""
+	}
+
+	return strings.Join(section.Body, " ")
+}
+
+func alignRun(cmd *cobra.Command, args []string) error {
+	english, err := openAndParseRules(cmd)
+	if err != nil {
+		return err
+	}
+
+	translated, err := openAndParseTranslation(cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !translated.EffectiveDate.Equal(english.EffectiveDate) {
+		cmd.Printf("the translation is effective as of %s and the English rules as of %s\n",
+			translated.EffectiveDate.Format("2006-01-02"), english.EffectiveDate.Format("2006-01-02"))
+	}
+
+	w := cmd.OutOrStdout()
+	aligned := parser.Align(english, translated)
+
+	if alignPairs {
+		for _, section := range aligned {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", section.ID, sectionText(section.English), sectionText(section.Translated))
+		}
+
+		return nil
+	}
+
+	var missing, extra int
+	for _, section := range aligned {
+		switch {
+		case section.Translated == nil:
+			missing++
+			fmt.Fprintf(w, "missing %s %s\n", section.ID, sectionText(section.English))
+		case section.English == nil:
+			extra++
+			fmt.Fprintf(w, "extra %s %s\n", section.ID, sectionText(section.Translated))
+		}
+	}
+
+	fmt.Fprintf(w, "%d of %d sections translated to %s, %d missing and %d not in the English rules\n",
+		len(english.Rules)-missing, len(english.Rules), translated.Language, missing, extra)
+
+	return nil
+}
+
+var alignCmd = &cobra.Command{
+	Use:   "align <translated rules>",
+	Short: "Pair the sections of translated rules with the English rules and report missing sections",
+	Example: `  rulesraker align MagicCompRules_FR.txt
+  rulesraker align --language de --pairs MagicCompRules_DE.txt`,
+	Args: cobra.ExactArgs(1),
+	RunE: alignRun,
+}
+
+func init() {
+	rootCmd.AddCommand(alignCmd)
+
+	alignCmd.Flags().StringVar(&alignLanguage, "language", "",
+		"language of the translated rules, detected from the section headers by default",
+	)
+	alignCmd.Flags().BoolVar(&alignPairs, "pairs", false,
+		"print every section of the English rules and the translation separated by tabs",
+	)
+}
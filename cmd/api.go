@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+var (
+	apiAddr string
+	apiAll  bool
+)
+
+// defaultSearchLimit is the number of search results returned when the limit
+// is not given.
+const defaultSearchLimit = 25
+
+// rulesVersions holds all of the versions of the rules served by the API
+// keyed by their effective date.
+type rulesVersions struct {
+	versions map[string]parser.Rules
+	latest   string
+}
+
+func newRulesVersions() *rulesVersions {
+	return &rulesVersions{versions: make(map[string]parser.Rules)}
+}
+
+func (rv *rulesVersions) add(rules parser.Rules) {
+	date := rules.EffectiveDate.Format("2006-01-02")
+	rv.versions[date] = rules
+
+	if date > rv.latest {
+		rv.latest = date
+	}
+}
+
+func (rv *rulesVersions) dates() []string {
+	dates := make([]string, 0, len(rv.versions))
+	for date := range rv.versions {
+		dates = append(dates, date)
+	}
+	slices.Sort(dates)
+
+	return dates
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, apiError{fmt.Sprintf(format, args...)})
+}
+
+// handle wraps an API handler so that it receives the version of the rules
+// selected with the version query parameter, defaulting to the latest one.
+// Responses are cached based on the effective date of the selected version.
+func (rv *rulesVersions) handle(handler func(w http.ResponseWriter, r *http.Request, rules parser.Rules)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		version := r.URL.Query().Get("version")
+		if version == "" {
+			version = rv.latest
+		}
+
+		rules, ok := rv.versions[version]
+		if !ok {
+			writeError(w, http.StatusNotFound, "version %q not found", version)
+			return
+		}
+
+		etag := strconv.Quote(version)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		handler(w, r, rules)
+	}
+}
+
+type apiVersion struct {
+	EffectiveDate string `json:"effectiveDate"`
+	Latest        bool   `json:"latest"`
+	Sections      int    `json:"sections"`
+	GlossaryItems int    `json:"glossaryItems"`
+}
+
+func (rv *rulesVersions) versionsHandler(w http.ResponseWriter, r *http.Request) {
+	var out []apiVersion
+	for _, date := range rv.dates() {
+		rules := rv.versions[date]
+		out = append(out, apiVersion{
+			EffectiveDate: date,
+			Latest:        date == rv.latest,
+			Sections:      len(rules.Rules),
+			GlossaryItems: len(rules.Glossary),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, out)
+}
+
+func ruleHandler(w http.ResponseWriter, r *http.Request, rules parser.Rules) {
+	id := r.PathValue("id")
+
+	section, ok := rules.Section(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "rule %q not found", id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, section)
+}
+
+func ruleChildrenHandler(w http.ResponseWriter, r *http.Request, rules parser.Rules) {
+	id := r.PathValue("id")
+
+	children, ok := rules.Children(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "rule %q not found", id)
+		return
+	}
+
+	if children == nil {
+		children = []parser.Section{}
+	}
+
+	writeJSON(w, http.StatusOK, children)
+}
+
+func glossaryHandler(w http.ResponseWriter, r *http.Request, rules parser.Rules) {
+	term := r.PathValue("term")
+
+	item, ok := rules.GlossaryItem(term)
+	if !ok {
+		writeError(w, http.StatusNotFound, "glossary term %q not found", term)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, item)
+}
+
+func cardHandler(w http.ResponseWriter, r *http.Request, rules parser.Rules) {
+	name := r.PathValue("name")
+
+	sections := rules.SectionsMentioningCard(name)
+	if len(sections) == 0 {
+		writeError(w, http.StatusNotFound, "no rules mention the card %q", name)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sections)
+}
+
+type apiCitation struct {
+	Ref           string `json:"ref"`
+	EffectiveDate string `json:"effectiveDate"`
+	Format        string `json:"format"`
+	Citation      string `json:"citation"`
+}
+
+func citeHandler(w http.ResponseWriter, r *http.Request, rules parser.Rules) {
+	ref := r.PathValue("ref")
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "text"
+	}
+
+	c, err := rules.Cite(ref)
+	if errors.Is(err, parser.ErrRuleNotFound) {
+		writeError(w, http.StatusNotFound, "%v", err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	citation, err := formatCitation(c, format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apiCitation{
+		Ref:           c.Ref,
+		EffectiveDate: c.EffectiveDate.Format("2006-01-02"),
+		Format:        format,
+		Citation:      citation,
+	})
+}
+
+func searchHandler(w http.ResponseWriter, r *http.Request, rules parser.Rules) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "query parameter q is required")
+		return
+	}
+
+	limit := defaultSearchLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			writeError(w, http.StatusBadRequest, "invalid limit %q", value)
+			return
+		}
+	}
+
+	results := rules.Search(query, limit)
+	if results == nil {
+		results = []parser.SearchResult{}
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (rv *rulesVersions) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /versions", rv.versionsHandler)
+	mux.HandleFunc("GET /rules/{id}", rv.handle(ruleHandler))
+	mux.HandleFunc("GET /rules/{id}/children", rv.handle(ruleChildrenHandler))
+	mux.HandleFunc("GET /glossary/{term}", rv.handle(glossaryHandler))
+	mux.HandleFunc("GET /cards/{name}", rv.handle(cardHandler))
+	mux.HandleFunc("GET /cite/{ref}", rv.handle(citeHandler))
+	mux.HandleFunc("GET /search", rv.handle(searchHandler))
+
+	return mux
+}
+
+func apiRun(cmd *cobra.Command, args []string) error {
+	rv := newRulesVersions()
+
+	err := forEachRules(cmd, apiAll, func(file string, rules parser.Rules) error {
+		rv.add(rules)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              apiAddr,
+		Handler:           rv.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	cmd.Printf("serving %d versions of the rules on http://%s\n", len(rv.versions), apiAddr)
+	return srv.ListenAndServe()
+}
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Serve the parsed rules as a JSON HTTP API",
+	Args:  cobra.NoArgs,
+	RunE:  apiRun,
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+
+	apiCmd.Flags().StringVar(&apiAddr, "addr", "localhost:8081",
+		"address to serve the API on",
+	)
+	apiCmd.Flags().BoolVar(&apiAll, "all", false,
+		"also serve all of the parseable versions of the rules in the archive",
+	)
+}
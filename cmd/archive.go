@@ -2,8 +2,6 @@ package cmd
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -12,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/xremming/rulesraker/archiver"
+	"github.com/xremming/rulesraker/parser"
 )
 
 var (
@@ -24,18 +23,7 @@ var (
 func archiveRun(cmd *cobra.Command, args []string) error {
 	now := time.Now().UTC()
 
-	fp, err := os.Open(filepath.Join(archiveDir, "metadata.json"))
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("metadata.json not found in %s", archiveDir)
-		}
-
-		return err
-	}
-	defer fp.Close()
-
-	var metadata archiver.Metadata
-	err = json.NewDecoder(fp).Decode(&metadata)
+	metadata, err := readMetadata()
 	if err != nil {
 		return err
 	}
@@ -75,6 +63,9 @@ func archiveRun(cmd *cobra.Command, args []string) error {
 
 			for possibleURL := range jobs {
 				logPrefix := possibleURL.Date.Format("2006-01-02")
+				if possibleURL.Document != parser.CR {
+					logPrefix += " " + string(possibleURL.Document)
+				}
 
 				if !possibleURL.Available {
 					cmd.Println(logPrefix, "possible url marked as unavailable, skipping", possibleURL.URL.String())
@@ -100,16 +91,20 @@ func archiveRun(cmd *cobra.Command, args []string) error {
 
 				cmd.Println(logPrefix, "rules found")
 				url := possibleURL.URL.String()
+
+				// The document is left out for the comprehensive rules to
+				// keep the metadata of the older files as is.
+				document := possibleURL.Document
+				if document == parser.CR {
+					document = ""
+				}
+
 				results <- archiver.Rule{
-					Date:   archiver.JSONDate(possibleURL.Date),
-					Format: possibleURL.Format,
-					File: fmt.Sprintf(
-						"%s/%s.%s",
-						possibleURL.Format,
-						possibleURL.Date.Format("2006-01-02"),
-						possibleURL.Format,
-					),
-					URL: &url,
+					Document: document,
+					Date:     archiver.JSONDate(possibleURL.Date),
+					Format:   possibleURL.Format,
+					File:     archiver.DocumentPath(possibleURL.Document, possibleURL.Date, possibleURL.Format),
+					URL:      &url,
 					ResponseMetadata: &archiver.ResponseMetadata{
 						ContentLength: resp.ContentLength,
 						ContentType:   resp.Header.Get("Content-Type"),
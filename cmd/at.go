@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var atRecursive bool
+
+func atRun(cmd *cobra.Command, args []string) error {
+	date, err := time.Parse("2006-01-02", args[0])
+	if err != nil {
+		return fmt.Errorf("invalid date format: %v", err)
+	}
+
+	rules, err := openArchiveRulesOn(cmd, date)
+	if err != nil {
+		return err
+	}
+
+	f := newTerminalFormatter(rules)
+
+	for i, id := range args[1:] {
+		section, ok := rules.Section(id)
+		if !ok {
+			return fmt.Errorf("rule %q not found in the rules effective %s", id, rules.EffectiveDate.Format("2006-01-02"))
+		}
+
+		if i > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+
+		printSectionTree(cmd.OutOrStdout(), f, rules, section, atRecursive)
+	}
+
+	return nil
+}
+
+var atCmd = &cobra.Command{
+	Use:   "at <date> <rule>...",
+	Short: "Show a rule as it was in the rules in effect on the date",
+	Long: `Show a rule as it was in the rules in effect on the date. The release in
+effect is resolved from the known releases in the archive metadata and parsed
+from its archived text file. Only releases archived as .txt can be parsed,
+releases archived only as .docx or .pdf are reported as such.`,
+	Example: `  rulesraker at 2019-06-01 702.19b
+  rulesraker at --recursive 2015-01-01 116`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: atRun,
+}
+
+func init() {
+	rootCmd.AddCommand(atCmd)
+
+	atCmd.Flags().BoolVarP(&atRecursive, "recursive", "r", false,
+		"show all of the rules below the rule instead of only the direct subrules",
+	)
+	atCmd.Flags().BoolVar(&noColor, "no-color", false,
+		"disable colored output",
+	)
+}
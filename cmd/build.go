@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"errors"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 
-	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/archiver"
+	"github.com/xremming/rulesraker/parser"
 )
 
 var (
@@ -15,9 +19,32 @@ var (
 	publicDir   string
 )
 
-func buildRun(cmd *cobra.Command, args []string) error {
-	cmd.Println("updating symbols from Scryfall")
-	symbolReplacer, err := getSymbolsReplacer()
+// renderToFile creates the file name and writes its contents with render.
+func renderToFile(name string, render func(w io.Writer) error) error {
+	fp, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	err = render(fp)
+	if err != nil {
+		return errors.Join(err, fp.Close())
+	}
+
+	return fp.Close()
+}
+
+// buildSite builds the site into the output directory. When watching it keeps
+// rebuilding the site on changes and calls onRebuild after each successful
+// rebuild, onRebuild may be nil.
+func buildSite(cmd *cobra.Command, onRebuild func()) error {
+	cmd.Println("reading symbols from the local cache")
+	symbols, err := readSymbology()
+	if err != nil {
+		return err
+	}
+
+	symbolReplacer, err := getSymbolsReplacer(symbols, "symbols")
 	if err != nil {
 		return err
 	}
@@ -27,81 +54,176 @@ func buildRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	build := func() error {
-		indexHTML, err := os.Create(filepath.Join(outputDir, "index.html"))
-		if err != nil {
-			return err
-		}
-		defer indexHTML.Close()
+	metadata, err := readMetadata()
+	if err != nil {
+		return err
+	}
+
+	// The feed is rendered only once as parsing the whole archive is slow and
+	// the archive isn't expected to change while watching.
+	cmd.Println("rendering feed.xml")
+	feed := newFeed(cmd, metadata, func(date archiver.JSONDate) (parser.Rules, error) {
+		return openAndParseArchiveRules(metadata, date)
+	})
+	err = renderToFile(filepath.Join(outputDir, "feed.xml"), func(w io.Writer) error {
+		return renderFeed(w, feed)
+	})
+	if err != nil {
+		return err
+	}
+
+	render := func() error {
+		data := newIndexData(rules)
 
 		cmd.Println("rendering index.html")
-		err = renderIndex(indexHTML, rules, symbolReplacer)
+		err := renderToFile(filepath.Join(outputDir, "index.html"), func(w io.Writer) error {
+			return renderIndex(w, data, symbolReplacer)
+		})
 		if err != nil {
 			return err
 		}
 
-		// copy all files from publicDir to outputDir
-		public := os.DirFS(publicDir)
-		err = copyRecursive(cmd, public, outputDir)
+		for _, document := range []parser.DocumentKind{parser.MTR, parser.IPG} {
+			err = renderDocumentPage(cmd, document)
+			if err != nil {
+				return err
+			}
+		}
+
+		cmd.Println("rendering sitemap.xml")
+		err = renderToFile(filepath.Join(outputDir, "sitemap.xml"), func(w io.Writer) error {
+			return renderSitemap(w, data.SitemapEntries())
+		})
 		if err != nil {
 			return err
 		}
 
-		return nil
+		cmd.Println("rendering robots.txt")
+		return renderToFile(filepath.Join(outputDir, "robots.txt"), renderRobots)
 	}
 
-	err = build()
-	if err != nil {
-		cmd.PrintErrf("error when building: %v\n", err)
-		if !watch {
+	build := func() error {
+		err := render()
+		if err != nil {
 			return err
 		}
-	}
-
-	if watch {
-		cmd.Println("watching for file system changes")
 
-		w, err := fsnotify.NewWatcher()
+		// copy all files from publicDir to outputDir
+		public := os.DirFS(publicDir)
+		err = copyRecursive(cmd, public, outputDir)
 		if err != nil {
 			return err
 		}
 
-		err = w.Add(publicDir)
+		// copy the cached symbol images so that they are served from the site itself
+		err = os.MkdirAll(filepath.Join(outputDir, "symbols"), 0o755)
 		if err != nil {
 			return err
 		}
 
-		err = w.Add(templateDir)
+		err = copyRecursive(cmd, os.DirFS(symbolsDir()), filepath.Join(outputDir, "symbols"))
 		if err != nil {
 			return err
 		}
 
-		for {
-			select {
-			case event, ok := <-w.Events:
-				if !ok {
-					return nil
-				}
+		return nil
+	}
 
-				cmd.Printf("file %q changed, rebuilding\n", event.Name)
+	// rebuild does only the work needed for the changed files: the rules are
+	// reparsed only when the rules text changes, templates are parsed on every
+	// render and public files are copied one by one.
+	rebuild := func(changed []string) error {
+		var (
+			reparse  bool
+			rerender bool
+			errCopy  error
+		)
+		for _, name := range changed {
+			if name == filepath.Clean(rulesPath()) {
+				reparse = true
+				continue
+			}
 
-				err = build()
-				if err != nil {
-					cmd.PrintErrf("error when building: %v\n", err)
-				}
-			case err, ok := <-w.Errors:
-				if !ok {
-					return nil
+			if _, ok := relativeTo(templateDir, name); ok {
+				rerender = true
+				continue
+			}
+
+			if path, ok := relativeTo(publicDir, name); ok {
+				err := copyFile(cmd, os.DirFS(publicDir), path, outputDir)
+				if errors.Is(err, fs.ErrNotExist) {
+					cmd.Printf("file %q was removed, not copying it\n", name)
+					continue
 				}
 
-				return err
+				errCopy = errors.Join(errCopy, err)
+			}
+		}
+
+		if reparse {
+			newRules, err := openAndParseRules(cmd)
+			if err != nil {
+				return errors.Join(errCopy, err)
 			}
+
+			rules = newRules
+			rerender = true
+		}
+
+		if rerender {
+			return errors.Join(errCopy, render())
+		}
+
+		return errCopy
+	}
+
+	err = build()
+	if err != nil {
+		cmd.PrintErrf("error when building: %v\n", err)
+		if !watch {
+			return err
 		}
 	}
 
+	if watch {
+		return watchChanges(cmd, []string{dataDir, publicDir, templateDir}, func(changed []string) {
+			cmd.Printf("files %q changed, rebuilding\n", changed)
+
+			err := rebuild(changed)
+			if err != nil {
+				cmd.PrintErrf("error when building: %v\n", err)
+				return
+			}
+
+			if onRebuild != nil {
+				onRebuild()
+			}
+		})
+	}
+
 	return nil
 }
 
+func buildRun(cmd *cobra.Command, args []string) error {
+	return buildSite(cmd, nil)
+}
+
+// addBuildFlags adds the flags controlling how the site is built to cmd.
+func addBuildFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false,
+		"watch for file system changes",
+	)
+	cmd.Flags().StringVarP(&outputDir, "out", "o", "dist",
+		"directory where the site will be rendered",
+	)
+	cmd.Flags().StringVar(&templateDir, "template", "template",
+		"directory which contains the templates for rendering",
+	)
+	cmd.Flags().StringVar(&publicDir, "public", "public",
+		"directory which contains files that will be copied as-is to the output directory",
+	)
+}
+
 var buildCmd = &cobra.Command{
 	Use:     "build",
 	Aliases: []string{"b"},
@@ -115,16 +237,5 @@ var buildCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(buildCmd)
-	buildCmd.Flags().BoolVarP(&watch, "watch", "w", false,
-		"watch for file system changes",
-	)
-	buildCmd.Flags().StringVarP(&outputDir, "out", "o", "dist",
-		"directory where the site will be rendered",
-	)
-	buildCmd.Flags().StringVar(&templateDir, "template", "template",
-		"directory which contains the templates for rendering",
-	)
-	buildCmd.Flags().StringVar(&publicDir, "public", "public",
-		"directory which contains files that will be copied as-is to the output directory",
-	)
+	addBuildFlags(buildCmd)
 }
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+func cardsRun(cmd *cobra.Command, args []string) error {
+	rules, err := openParsedRules()
+	if err != nil {
+		return err
+	}
+
+	f := newTerminalFormatter(rules)
+	w := cmd.OutOrStdout()
+
+	if len(args) == 0 {
+		var names []string
+		for _, section := range rules.Rules {
+			for _, name := range section.Cards {
+				if !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			fmt.Fprintln(w, name)
+		}
+
+		return nil
+	}
+
+	for i, name := range args {
+		sections := rules.SectionsMentioningCard(name)
+		if len(sections) == 0 {
+			return fmt.Errorf("no rules mention the card %q", name)
+		}
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		for _, section := range sections {
+			fmt.Fprint(w, f.Section(section))
+		}
+	}
+
+	return nil
+}
+
+func cardsSyncRun(cmd *cobra.Command, args []string) error {
+	cmd.Printf("getting bulk data from %q\n", scryfallBulkDataURL)
+	resp, err := scryfallGet(scryfallBulkDataURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var bulkData ScryfallBulkData
+	err = json.NewDecoder(resp.Body).Decode(&bulkData)
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("downloading cards updated at %s from %q\n", bulkData.UpdatedAt, bulkData.DownloadURI)
+	cards, err := scryfallGet(bulkData.DownloadURI)
+	if err != nil {
+		return err
+	}
+	defer cards.Body.Close()
+
+	return writeFileAtomic(cardsFile, cards.Body)
+}
+
+var cardsCmd = &cobra.Command{
+	Use:   "cards [name]...",
+	Short: "List the cards mentioned in the rules or show the rules which mention some of them",
+	Long: `List the cards mentioned in the rules or show the rules which mention some of
+them. The cards are detected only when parsing the rules with --cards, using
+the Scryfall bulk data downloaded by cards sync.`,
+	Example: `  rulesraker cards sync --cards oracle-cards.json && rulesraker parse --cards oracle-cards.json
+  rulesraker cards "Llanowar Elves"`,
+	RunE: cardsRun,
+}
+
+var cardsSyncCmd = &cobra.Command{
+	Use:     "sync",
+	Short:   "Download the Scryfall bulk data of the cards used to detect card names to the --cards file",
+	Example: `  rulesraker cards sync --cards oracle-cards.json`,
+	Args:    cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if cardsFile == "" {
+			return errors.New("the file to download the cards to must be given with --cards")
+		}
+
+		return os.MkdirAll(filepath.Dir(cardsFile), 0o755)
+	},
+	RunE: cardsSyncRun,
+}
+
+func init() {
+	rootCmd.AddCommand(cardsCmd)
+	cardsCmd.AddCommand(cardsSyncCmd)
+
+	cardsCmd.Flags().BoolVar(&noColor, "no-color", false,
+		"disable colored output",
+	)
+}
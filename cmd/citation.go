@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/xremming/rulesraker/parser"
+)
+
+// citationFormats are the formats citations can be written in.
+var citationFormats = []string{"text", "markdown", "html"}
+
+// citationSource returns the name of the cited rules and the version they are
+// cited from.
+func citationSource(c parser.Citation) (string, string) {
+	return "Magic: The Gathering Comprehensive Rules " + c.Ref,
+		"effective " + c.EffectiveDate.Format("January 2, 2006")
+}
+
+// citationText writes the citation as plain text in the same layout as the
+// official .txt file.
+func citationText(c parser.Citation) string {
+	var out strings.Builder
+	for _, section := range c.Sections {
+		for i, line := range section.Body {
+			if i == 0 {
+				fmt.Fprintf(&out, "%s %s\n", section.Number, line)
+			} else {
+				fmt.Fprintf(&out, "%s\n", line)
+			}
+		}
+
+		for _, example := range section.Examples {
+			fmt.Fprintf(&out, "Example: %s\n", example)
+		}
+	}
+
+	name, version := citationSource(c)
+	fmt.Fprintf(&out, "\n— %s, %s. %s\n", name, version, rulesURL)
+
+	return out.String()
+}
+
+// citationMarkdown writes the citation as a Markdown block quote.
+func citationMarkdown(c parser.Citation) string {
+	var out strings.Builder
+	for _, section := range c.Sections {
+		for i, line := range section.Body {
+			if i == 0 {
+				fmt.Fprintf(&out, "> **%s** %s\n>\n", markdownEscaper.Replace(section.Number), markdownEscaper.Replace(line))
+			} else {
+				fmt.Fprintf(&out, "> %s\n>\n", markdownEscaper.Replace(line))
+			}
+		}
+
+		for _, example := range section.Examples {
+			fmt.Fprintf(&out, "> *Example:* %s\n>\n", markdownEscaper.Replace(example))
+		}
+	}
+
+	name, version := citationSource(c)
+	fmt.Fprintf(&out, "> — [%s](%s), %s\n", markdownEscaper.Replace(name), rulesURL, version)
+
+	return out.String()
+}
+
+// citationHTML writes the citation as an HTML block quote.
+func citationHTML(c parser.Citation) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "<blockquote cite=\"%s\">\n", html.EscapeString(rulesURL))
+	for _, section := range c.Sections {
+		for i, line := range section.Body {
+			if i == 0 {
+				fmt.Fprintf(&out, "<p><b>%s</b> %s</p>\n", html.EscapeString(section.Number), html.EscapeString(line))
+			} else {
+				fmt.Fprintf(&out, "<p>%s</p>\n", html.EscapeString(line))
+			}
+		}
+
+		for _, example := range section.Examples {
+			fmt.Fprintf(&out, "<p><i>Example:</i> %s</p>\n", html.EscapeString(example))
+		}
+	}
+
+	name, version := citationSource(c)
+	fmt.Fprintf(&out, "<footer>— <cite><a href=\"%s\">%s</a></cite>, %s</footer>\n</blockquote>\n",
+		html.EscapeString(rulesURL), html.EscapeString(name), version)
+
+	return out.String()
+}
+
+// formatCitation writes the citation in one of the citation formats.
+func formatCitation(c parser.Citation, format string) (string, error) {
+	switch format {
+	case "text":
+		return citationText(c), nil
+	case "markdown":
+		return citationMarkdown(c), nil
+	case "html":
+		return citationHTML(c), nil
+	}
+
+	return "", fmt.Errorf("unknown citation format %q, expected one of %s", format, strings.Join(citationFormats, ", "))
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+var (
+	citeFormat string
+	citeAt     FlagDate
+)
+
+// citedRules returns the current rules, or the rules in effect on the date
+// given with --at.
+func citedRules(cmd *cobra.Command) (parser.Rules, error) {
+	if time.Time(citeAt).IsZero() {
+		return openParsedRules()
+	}
+
+	return openArchiveRulesOn(cmd, time.Time(citeAt))
+}
+
+func citeRun(cmd *cobra.Command, args []string) error {
+	rules, err := citedRules(cmd)
+	if err != nil {
+		return err
+	}
+
+	for i, ref := range args {
+		c, err := rules.Cite(ref)
+		if err != nil {
+			return err
+		}
+
+		out, err := formatCitation(c, citeFormat)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+
+		fmt.Fprint(cmd.OutOrStdout(), out)
+	}
+
+	return nil
+}
+
+var citeCmd = &cobra.Command{
+	Use:   "cite <rule>...",
+	Short: "Write a citation of a rule or a range of rules with their subrules and examples",
+	Long: `Write a citation of a rule or a range of rules with their subrules and
+examples, ready to be pasted into tournament reports and appeals. The citation
+names the version of the rules it was cited from.`,
+	Example: `  rulesraker cite 702.19b
+  rulesraker cite --format markdown 601.2a-f
+  rulesraker cite --at 2019-06-01 --format html 702.2`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: citeRun,
+}
+
+func init() {
+	rootCmd.AddCommand(citeCmd)
+
+	citeCmd.Flags().StringVar(&citeFormat, "format", "text",
+		"format of the citation, text, markdown or html",
+	)
+	citeCmd.Flags().Var(&citeAt, "at",
+		"cite the rules in effect on the date instead of the current rules",
+	)
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -10,19 +9,12 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"github.com/xremming/rulesraker/archiver"
 )
 
 var downloadAlways bool
 
 func downloadRun(cmd *cobra.Command, args []string) error {
-	fp, err := os.Open(filepath.Join(archiveDir, "metadata.json"))
-	if err != nil {
-		return err
-	}
-
-	var metadata archiver.Metadata
-	err = json.NewDecoder(fp).Decode(&metadata)
+	metadata, err := readMetadata()
 	if err != nil {
 		return err
 	}
@@ -62,7 +54,7 @@ func downloadRun(cmd *cobra.Command, args []string) error {
 			Header: headers,
 		}
 
-		cmd.Println("downloading", file.URL)
+		cmd.Println("downloading", *file.URL)
 		resp, err := http.DefaultClient.Do(&req)
 		if err != nil {
 			return err
@@ -74,10 +66,15 @@ func downloadRun(cmd *cobra.Command, args []string) error {
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			cmd.Printf("downloading %q returned a non 200 status code\n", file.URL)
+			cmd.Printf("downloading %q returned a non 200 status code\n", *file.URL)
 			continue
 		}
 
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			return err
+		}
+
 		fp, err := os.Create(path)
 		if err != nil {
 			return err
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the parsed rules into other formats",
+	Args:  cobra.NoArgs,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+}
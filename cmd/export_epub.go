@@ -0,0 +1,424 @@
+package cmd
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+var exportEPUBOut string
+
+const epubContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+const epubStyle = `body { font-family: serif; line-height: 1.4; }
+h1, h2 { font-family: sans-serif; }
+.number { font-weight: bold; }
+.example { font-style: italic; margin-left: 1em; }
+.symbol { height: 1em; vertical-align: text-bottom; }
+dt { font-weight: bold; margin-top: 1em; }
+`
+
+// epubFile is a single file in the OEBPS directory of the EPUB.
+type epubFile struct {
+	ID         string
+	Name       string
+	MediaType  string
+	Properties string
+	Spine      bool
+	Content    []byte
+}
+
+// epubExporter renders the rules as an EPUB 3 with one XHTML document for each
+// chapter. Symbols are embedded as images from the local symbol cache.
+type epubExporter struct {
+	rules     parser.Rules
+	symbols   *strings.Replacer
+	tokenizer *parser.Tokenizer
+}
+
+func newEPUBExporter(rules parser.Rules, symbols *strings.Replacer) epubExporter {
+	return epubExporter{rules, symbols, parser.NewTokenizer(rules)}
+}
+
+// epubChapterFile returns the file of the chapter of the section with the ID,
+// parts are rendered into the file of their first chapter.
+func epubChapterFile(id string) string {
+	n, _ := parser.ParseRuleNumber(id)
+	if n.Type() == parser.Part {
+		n.Chapter = 100 * n.Part
+	}
+
+	return fmt.Sprintf("chapter-%d.xhtml", n.Chapter)
+}
+
+func epubRuleAnchor(id string) string {
+	return "rule-" + id
+}
+
+func epubGlossaryAnchor(id string) string {
+	return "glossary-" + id
+}
+
+func epubRuleLink(id string) string {
+	return epubChapterFile(id) + "#" + epubRuleAnchor(id)
+}
+
+// text escapes the text, turns references to rules and glossary items into
+// links and replaces symbols with images.
+func (e epubExporter) text(s string) string {
+	var out strings.Builder
+	for _, inline := range e.tokenizer.Tokenize(s) {
+		text := html.EscapeString(inline.Text)
+
+		switch inline.Kind {
+		case parser.InlineSymbol:
+			out.WriteString(e.symbols.Replace(text))
+		case parser.InlineRuleRef:
+			fmt.Fprintf(&out, `<a href="%s">%s</a>`, epubRuleLink(inline.Target), text)
+		case parser.InlineGlossaryTerm:
+			fmt.Fprintf(&out, `<a href="glossary.xhtml#%s">%s</a>`, epubGlossaryAnchor(inline.Target), text)
+		case parser.InlineLink:
+			fmt.Fprintf(&out, `<a href="%s">%s</a>`, html.EscapeString(inline.Target), text)
+		case parser.InlineDocumentRef:
+			fmt.Fprintf(&out, `<a href="%s">%s</a>`, html.EscapeString(documentRefURL(inline.Target)), text)
+		case parser.InlineCard:
+			fmt.Fprintf(&out, `<a href="%s">%s</a>`, html.EscapeString(scryfallCardURL(inline.Target)), text)
+		default:
+			out.WriteString(text)
+		}
+	}
+
+	return out.String()
+}
+
+func epubDocument(title, body string) []byte {
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="en" lang="en">
+<head>
+  <meta charset="UTF-8"/>
+  <title>%s</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+%s</body>
+</html>
+`, html.EscapeString(title), body))
+}
+
+func (e epubExporter) section(w io.Writer, section parser.Section) {
+	anchor := epubRuleAnchor(section.ID)
+
+	switch section.Type {
+	case parser.Part:
+		fmt.Fprintf(w, "<h1 id=\"%s\">%s %s</h1>\n", anchor, section.Number, html.EscapeString(section.Body[0]))
+		return
+	case parser.Chapter:
+		fmt.Fprintf(w, "<h2 id=\"%s\">%s %s</h2>\n", anchor, section.Number, html.EscapeString(section.Body[0]))
+		return
+	}
+
+	for i, line := range section.Body {
+		if i == 0 {
+			fmt.Fprintf(w, "<p id=\"%s\"><span class=\"number\">%s</span> %s</p>\n", anchor, section.Number, e.text(line))
+		} else {
+			fmt.Fprintf(w, "<p>%s</p>\n", e.text(line))
+		}
+	}
+
+	for _, example := range section.Examples {
+		fmt.Fprintf(w, "<p class=\"example\"><b>Example:</b> %s</p>\n", e.text(example))
+	}
+}
+
+func (e epubExporter) titlePage() epubFile {
+	body := fmt.Sprintf(
+		"<h1>Magic: The Gathering Comprehensive Rules</h1>\n<p>These rules are effective as of %s.</p>\n",
+		e.rules.EffectiveDate.Format("January 2, 2006"),
+	)
+
+	return epubFile{"title", "title.xhtml", "application/xhtml+xml", "", true, epubDocument("Comprehensive Rules", body)}
+}
+
+func (e epubExporter) chapters() []epubFile {
+	var (
+		out     []epubFile
+		current *strings.Builder
+		title   string
+		name    string
+	)
+	flush := func() {
+		if current == nil {
+			return
+		}
+
+		id := strings.TrimSuffix(name, ".xhtml")
+		out = append(out, epubFile{id, name, "application/xhtml+xml", "", true, epubDocument(title, current.String())})
+		current = nil
+	}
+
+	var part *parser.Section
+	for _, section := range e.rules.Rules {
+		if section.Type == parser.Part {
+			part = &section
+			continue
+		}
+
+		if section.Type == parser.Chapter {
+			flush()
+			current = &strings.Builder{}
+			title = section.Number + " " + section.Body[0]
+			name = epubChapterFile(section.ID)
+
+			// Parts are rendered at the beginning of their first chapter.
+			if part != nil {
+				e.section(current, *part)
+				part = nil
+			}
+		}
+
+		if current != nil {
+			e.section(current, section)
+		}
+	}
+	flush()
+
+	return out
+}
+
+func (e epubExporter) glossary() epubFile {
+	var body strings.Builder
+	body.WriteString("<h1 id=\"glossary\">Glossary</h1>\n<dl>\n")
+	for _, item := range e.rules.Glossary {
+		fmt.Fprintf(&body, "<dt id=\"%s\">%s</dt>\n", epubGlossaryAnchor(item.ID), html.EscapeString(item.KeyText))
+		for _, line := range strings.Split(item.Body, "\n") {
+			fmt.Fprintf(&body, "<dd>%s</dd>\n", e.text(line))
+		}
+	}
+	body.WriteString("</dl>\n")
+
+	return epubFile{"glossary", "glossary.xhtml", "application/xhtml+xml", "", true, epubDocument("Glossary", body.String())}
+}
+
+func (e epubExporter) credits() epubFile {
+	var body strings.Builder
+	body.WriteString("<h1 id=\"credits\">Credits</h1>\n")
+	for _, credit := range e.rules.Credits.Paragraphs {
+		fmt.Fprintf(&body, "<p>%s</p>\n", strings.ReplaceAll(html.EscapeString(credit), "\n", "<br/>"))
+	}
+
+	return epubFile{"credits", "credits.xhtml", "application/xhtml+xml", "", true, epubDocument("Credits", body.String())}
+}
+
+// nav returns the navigation document of the parts and their chapters.
+func (e epubExporter) nav() epubFile {
+	var body strings.Builder
+	body.WriteString("<nav epub:type=\"toc\" id=\"toc\">\n<h1>Contents</h1>\n<ol>\n")
+
+	inPart := false
+	for _, section := range e.rules.Rules {
+		switch section.Type {
+		case parser.Part:
+			if inPart {
+				body.WriteString("</ol></li>\n")
+			}
+			inPart = true
+
+			// Parts are rendered at the beginning of their first chapter.
+			fmt.Fprintf(&body, "<li><a href=\"%s\">%s %s</a><ol>\n", epubRuleLink(section.ID), section.Number, html.EscapeString(section.Body[0]))
+		case parser.Chapter:
+			fmt.Fprintf(&body, "<li><a href=\"%s\">%s %s</a></li>\n", epubRuleLink(section.ID), section.Number, html.EscapeString(section.Body[0]))
+		}
+	}
+	if inPart {
+		body.WriteString("</ol></li>\n")
+	}
+
+	body.WriteString("<li><a href=\"glossary.xhtml#glossary\">Glossary</a></li>\n")
+	body.WriteString("<li><a href=\"credits.xhtml#credits\">Credits</a></li>\n")
+	body.WriteString("</ol>\n</nav>\n")
+
+	return epubFile{"nav", "nav.xhtml", "application/xhtml+xml", "nav", false, epubDocument("Contents", body.String())}
+}
+
+func epubPackage(rules parser.Rules, files []epubFile) []byte {
+	date := rules.EffectiveDate.Format("2006-01-02")
+
+	var out strings.Builder
+	fmt.Fprintf(&out, `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="id" xml:lang="en">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="id">urn:rulesraker:comprehensive-rules:%s</dc:identifier>
+    <dc:title>Magic: The Gathering Comprehensive Rules (%s)</dc:title>
+    <dc:language>en</dc:language>
+    <dc:publisher>Wizards of the Coast</dc:publisher>
+    <dc:date>%s</dc:date>
+    <meta property="dcterms:modified">%s</meta>
+  </metadata>
+  <manifest>
+`, date, date, date, rules.EffectiveDate.UTC().Format(time.RFC3339))
+
+	for _, file := range files {
+		properties := ""
+		if file.Properties != "" {
+			properties = fmt.Sprintf(` properties="%s"`, file.Properties)
+		}
+
+		fmt.Fprintf(&out, "    <item id=\"%s\" href=\"%s\" media-type=\"%s\"%s/>\n", file.ID, file.Name, file.MediaType, properties)
+	}
+
+	out.WriteString("  </manifest>\n  <spine>\n")
+	for _, file := range files {
+		if file.Spine {
+			fmt.Fprintf(&out, "    <itemref idref=\"%s\"/>\n", file.ID)
+		}
+	}
+	out.WriteString("  </spine>\n</package>\n")
+
+	return []byte(out.String())
+}
+
+// symbolFiles returns the cached SVGs of the symbols.
+func symbolFiles(symbols CardSymbolList) ([]epubFile, error) {
+	var out []epubFile
+	seen := make(map[string]bool)
+	for i, cardSymbol := range symbols.Data {
+		fileName, err := cardSymbol.FileName()
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[fileName] {
+			continue
+		}
+		seen[fileName] = true
+
+		content, err := os.ReadFile(filepath.Join(symbolsDir(), fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, epubFile{fmt.Sprintf("symbol-%d", i), "symbols/" + fileName, "image/svg+xml", "", false, content})
+	}
+
+	return out, nil
+}
+
+func writeEPUB(w io.Writer, rules parser.Rules, files []epubFile) error {
+	zw := zip.NewWriter(w)
+
+	// The mimetype has to be the first file of the archive and stored
+	// without compression.
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(mimetype, "application/epub+zip")
+	if err != nil {
+		return err
+	}
+
+	container, err := zw.Create("META-INF/container.xml")
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(container, epubContainer)
+	if err != nil {
+		return err
+	}
+
+	pkg, err := zw.Create("OEBPS/content.opf")
+	if err != nil {
+		return err
+	}
+
+	_, err = pkg.Write(epubPackage(rules, files))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		fw, err := zw.Create("OEBPS/" + file.Name)
+		if err != nil {
+			return err
+		}
+
+		_, err = fw.Write(file.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func exportEPUBRun(cmd *cobra.Command, args []string) error {
+	symbols, err := readSymbology()
+	if err != nil {
+		return err
+	}
+
+	symbolReplacer, err := newSymbolsReplacer(symbols, func(symbol, fileName string) string {
+		return fmt.Sprintf(`<img class="symbol" alt="%s" src="symbols/%s"/>`, symbol, fileName)
+	})
+	if err != nil {
+		return err
+	}
+
+	rules, err := openAndParseRules(cmd)
+	if err != nil {
+		return err
+	}
+
+	e := newEPUBExporter(rules, symbolReplacer)
+
+	files := []epubFile{
+		{"style", "style.css", "text/css", "", false, []byte(epubStyle)},
+		e.nav(),
+		e.titlePage(),
+	}
+	files = append(files, e.chapters()...)
+	files = append(files, e.glossary(), e.credits())
+
+	symbolFiles, err := symbolFiles(symbols)
+	if err != nil {
+		return err
+	}
+	files = append(files, symbolFiles...)
+
+	cmd.Printf("writing epub to %q\n", exportEPUBOut)
+	return renderToFile(exportEPUBOut, func(w io.Writer) error {
+		return writeEPUB(w, rules, files)
+	})
+}
+
+var exportEPUBCmd = &cobra.Command{
+	Use:   "epub",
+	Short: "Export the current rules as an EPUB for e-readers",
+	Args:  cobra.NoArgs,
+	RunE:  exportEPUBRun,
+}
+
+func init() {
+	exportCmd.AddCommand(exportEPUBCmd)
+
+	exportEPUBCmd.Flags().StringVarP(&exportEPUBOut, "out", "o", "MagicCompRules.epub",
+		"file to write the epub to",
+	)
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+var (
+	exportMarkdownOut   string
+	exportMarkdownSplit bool
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `&lt;`,
+	`>`, `&gt;`,
+)
+
+// markdownExporter renders the rules as Markdown. Every section and glossary
+// item gets an explicit anchor matching its ID so that links to the rules stay
+// stable between releases. When split the rules are rendered into one file per
+// chapter and links between the files are relative. Errors are collected into
+// err while rendering.
+type markdownExporter struct {
+	split     bool
+	rules     parser.Rules
+	tokenizer *parser.Tokenizer
+	err       error
+}
+
+const (
+	markdownIndexFile    = "README.md"
+	markdownGlossaryFile = "glossary.md"
+)
+
+// chapterFile returns the file the chapter of the section with the ID is
+// rendered to when splitting.
+func chapterFile(id string) string {
+	n, _ := parser.ParseRuleNumber(id)
+	return path.Join(strconv.Itoa(n.Part), strconv.Itoa(n.Chapter)+".md")
+}
+
+func glossaryAnchor(id string) string {
+	return "glossary-" + id
+}
+
+// link returns a link from the file to the anchor in the target file.
+func (e *markdownExporter) link(from, target, anchor string) string {
+	if !e.split || from == target {
+		return "#" + anchor
+	}
+
+	rel, err := filepath.Rel(path.Dir(from), target)
+	if err != nil {
+		e.err = errors.Join(e.err, fmt.Errorf("linking %s to %s: %w", from, target, err))
+		return "#" + anchor
+	}
+
+	return filepath.ToSlash(rel) + "#" + anchor
+}
+
+// text escapes the text and turns references to rules and glossary items into
+// links.
+func (e *markdownExporter) text(from, s string) string {
+	var out strings.Builder
+	for _, inline := range e.tokenizer.Tokenize(s) {
+		text := markdownEscaper.Replace(inline.Text)
+
+		switch inline.Kind {
+		case parser.InlineRuleRef:
+			target := markdownIndexFile
+			if n, _ := parser.ParseRuleNumber(inline.Target); n.Type() != parser.Part {
+				target = chapterFile(inline.Target)
+			}
+
+			fmt.Fprintf(&out, "[%s](%s)", text, e.link(from, target, inline.Target))
+		case parser.InlineGlossaryTerm:
+			fmt.Fprintf(&out, "[%s](%s)", text, e.link(from, markdownGlossaryFile, glossaryAnchor(inline.Target)))
+		case parser.InlineLink:
+			fmt.Fprintf(&out, "[%s](%s)", text, inline.Target)
+		case parser.InlineDocumentRef:
+			fmt.Fprintf(&out, "[%s](%s)", text, documentRefURL(inline.Target))
+		case parser.InlineCard:
+			fmt.Fprintf(&out, "[%s](%s)", text, scryfallCardURL(inline.Target))
+		default:
+			out.WriteString(text)
+		}
+	}
+
+	return out.String()
+}
+
+func (e *markdownExporter) writeSection(w io.Writer, from string, section parser.Section) {
+	anchor := fmt.Sprintf(`<a id="%s"></a>`, section.ID)
+
+	switch section.Type {
+	case parser.Part:
+		fmt.Fprintf(w, "# %s%s %s\n\n", anchor, section.Number, markdownEscaper.Replace(section.Body[0]))
+		return
+	case parser.Chapter:
+		fmt.Fprintf(w, "## %s%s %s\n\n", anchor, section.Number, markdownEscaper.Replace(section.Body[0]))
+		return
+	}
+
+	for i, line := range section.Body {
+		if i == 0 {
+			fmt.Fprintf(w, "%s**%s** %s\n\n", anchor, section.Number, e.text(from, line))
+		} else {
+			fmt.Fprintf(w, "%s\n\n", e.text(from, line))
+		}
+	}
+
+	for _, example := range section.Examples {
+		fmt.Fprintf(w, "> *Example:* %s\n\n", e.text(from, example))
+	}
+}
+
+func (e *markdownExporter) writeHeader(w io.Writer) {
+	fmt.Fprintf(w, "# Magic: The Gathering Comprehensive Rules\n\n")
+	fmt.Fprintf(w, "These rules are effective as of %s.\n\n", e.rules.EffectiveDate.Format("January 2, 2006"))
+}
+
+func (e *markdownExporter) writeGlossary(w io.Writer, from string) {
+	fmt.Fprintf(w, "# Glossary\n\n")
+	for _, item := range e.rules.Glossary {
+		fmt.Fprintf(w, "<a id=\"%s\"></a>**%s**\n\n", glossaryAnchor(item.ID), markdownEscaper.Replace(item.KeyText))
+		for _, line := range strings.Split(item.Body, "\n") {
+			fmt.Fprintf(w, "%s\n\n", e.text(from, line))
+		}
+	}
+}
+
+func (e *markdownExporter) writeCredits(w io.Writer) {
+	fmt.Fprintf(w, "# Credits\n\n")
+	for _, credit := range e.rules.Credits.Paragraphs {
+		fmt.Fprintf(w, "%s\n\n", strings.ReplaceAll(markdownEscaper.Replace(credit), "\n", "  \n"))
+	}
+}
+
+// writeSingle writes all of the rules into a single file.
+func (e *markdownExporter) writeSingle(w io.Writer) error {
+	e.writeHeader(w)
+	for _, section := range e.rules.Rules {
+		e.writeSection(w, "", section)
+	}
+	e.writeGlossary(w, "")
+	e.writeCredits(w)
+
+	return e.err
+}
+
+// writeTree writes the rules into the directory, the index file links to a
+// file for each chapter.
+func (e *markdownExporter) writeTree(dir string) error {
+	files := make(map[string]*strings.Builder)
+	file := func(name string) *strings.Builder {
+		if _, ok := files[name]; !ok {
+			files[name] = &strings.Builder{}
+		}
+
+		return files[name]
+	}
+
+	index := file(markdownIndexFile)
+	e.writeHeader(index)
+
+	for _, section := range e.rules.Rules {
+		switch section.Type {
+		case parser.Part:
+			fmt.Fprintf(index, "## <a id=\"%s\"></a>%s %s\n\n", section.ID, section.Number, markdownEscaper.Replace(section.Body[0]))
+			continue
+		case parser.Chapter:
+			fmt.Fprintf(index, "- [%s %s](%s)\n", section.Number, markdownEscaper.Replace(section.Body[0]), chapterFile(section.ID))
+		}
+
+		name := chapterFile(section.ID)
+		e.writeSection(file(name), name, section)
+	}
+
+	fmt.Fprintf(index, "\n## [Glossary](%s)\n\n", markdownGlossaryFile)
+	e.writeGlossary(file(markdownGlossaryFile), markdownGlossaryFile)
+	e.writeCredits(index)
+
+	if e.err != nil {
+		return e.err
+	}
+
+	var err error
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		err = errors.Join(err, os.MkdirAll(filepath.Dir(p), 0o755))
+		err = errors.Join(err, os.WriteFile(p, []byte(content.String()), 0o644))
+	}
+
+	return err
+}
+
+func exportMarkdownRun(cmd *cobra.Command, args []string) error {
+	rules, err := openAndParseRules(cmd)
+	if err != nil {
+		return err
+	}
+
+	e := &markdownExporter{split: exportMarkdownSplit, rules: rules, tokenizer: parser.NewTokenizer(rules)}
+
+	out := exportMarkdownOut
+	if e.split {
+		if out == "" {
+			out = "MagicCompRules"
+		}
+
+		cmd.Printf("writing markdown into %q\n", out)
+		return e.writeTree(out)
+	}
+
+	if out == "" {
+		out = "MagicCompRules.md"
+	}
+
+	cmd.Printf("writing markdown to %q\n", out)
+	return renderToFile(out, e.writeSingle)
+}
+
+var exportMarkdownCmd = &cobra.Command{
+	Use:   "markdown",
+	Short: "Export the current rules as Markdown",
+	Args:  cobra.NoArgs,
+	RunE:  exportMarkdownRun,
+}
+
+func init() {
+	exportCmd.AddCommand(exportMarkdownCmd)
+
+	exportMarkdownCmd.Flags().StringVarP(&exportMarkdownOut, "out", "o", "",
+		"file, or directory when splitting, to write the markdown to (default \"MagicCompRules.md\", or \"MagicCompRules\" when splitting)",
+	)
+	exportMarkdownCmd.Flags().BoolVar(&exportMarkdownSplit, "split", false,
+		"write a directory with a file for each chapter instead of a single file",
+	)
+}
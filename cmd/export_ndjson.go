@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+var (
+	exportNDJSONOut string
+	exportNDJSONAll bool
+)
+
+// ndjsonRecord is a single line of the NDJSON export, exactly one of Section
+// and GlossaryItem is set.
+type ndjsonRecord struct {
+	Kind          string               `json:"kind"`
+	EffectiveDate string               `json:"effectiveDate"`
+	Source        string               `json:"source"`
+	Format        string               `json:"format"`
+	Hash          string               `json:"hash"`
+	Section       *parser.Section      `json:"section,omitempty"`
+	GlossaryItem  *parser.GlossaryItem `json:"glossaryItem,omitempty"`
+}
+
+// contentHash returns the SHA-256 of the JSON encoding of the value, which
+// stays the same between versions as long as the content does not change.
+func contentHash(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// writeNDJSON writes one line for each of the sections and glossary items of
+// the rules.
+func writeNDJSON(enc *json.Encoder, file string, rules parser.Rules) error {
+	base := ndjsonRecord{
+		EffectiveDate: rules.EffectiveDate.Format("2006-01-02"),
+		Source:        file,
+		Format:        strings.TrimPrefix(filepath.Ext(file), "."),
+	}
+
+	for _, section := range rules.Rules {
+		hash, err := contentHash(section)
+		if err != nil {
+			return err
+		}
+
+		record := base
+		record.Kind = "section"
+		record.Hash = hash
+		record.Section = &section
+
+		err = enc.Encode(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, item := range rules.Glossary {
+		hash, err := contentHash(item)
+		if err != nil {
+			return err
+		}
+
+		record := base
+		record.Kind = "glossary"
+		record.Hash = hash
+		record.GlossaryItem = &item
+
+		err = enc.Encode(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func exportNDJSONRun(cmd *cobra.Command, args []string) (err error) {
+	var out io.Writer = cmd.OutOrStdout()
+	if exportNDJSONOut != "-" {
+		fp, err := os.Create(exportNDJSONOut)
+		if err != nil {
+			return err
+		}
+		defer func() { err = errors.Join(err, fp.Close()) }()
+
+		out = fp
+	}
+
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+
+	err = forEachRules(cmd, exportNDJSONAll, func(file string, rules parser.Rules) error {
+		cmd.Printf("exporting rules effective as of %s\n", rules.EffectiveDate.Format("2006-01-02"))
+
+		err := writeNDJSON(enc, file, rules)
+		if err != nil {
+			return err
+		}
+
+		return w.Flush()
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+var exportNDJSONCmd = &cobra.Command{
+	Use:   "ndjson",
+	Short: "Export the rules as newline delimited JSON, one line per section and glossary item",
+	Args:  cobra.NoArgs,
+	RunE:  exportNDJSONRun,
+}
+
+func init() {
+	exportCmd.AddCommand(exportNDJSONCmd)
+
+	exportNDJSONCmd.Flags().StringVarP(&exportNDJSONOut, "out", "o", "-",
+		"file to write the lines to, - writes them to stdout",
+	)
+	exportNDJSONCmd.Flags().BoolVar(&exportNDJSONAll, "all", false,
+		"export all of the parseable versions of the rules in the archive instead of only the current rules",
+	)
+}
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+	_ "modernc.org/sqlite"
+)
+
+var (
+	exportSQLiteOut string
+	exportSQLiteAll bool
+)
+
+const sqliteSchema = `
+CREATE TABLE versions (
+	id             INTEGER PRIMARY KEY,
+	effective_date TEXT NOT NULL UNIQUE,
+	file           TEXT NOT NULL
+);
+
+CREATE TABLE sections (
+	id         INTEGER PRIMARY KEY,
+	version_id INTEGER NOT NULL REFERENCES versions (id),
+	position   INTEGER NOT NULL,
+	rule_id    TEXT NOT NULL,
+	number     TEXT NOT NULL,
+	type       TEXT NOT NULL,
+	body       TEXT NOT NULL,
+	UNIQUE (version_id, rule_id)
+);
+
+CREATE TABLE examples (
+	id         INTEGER PRIMARY KEY,
+	section_id INTEGER NOT NULL REFERENCES sections (id),
+	position   INTEGER NOT NULL,
+	body       TEXT NOT NULL
+);
+
+CREATE TABLE glossary_items (
+	id          INTEGER PRIMARY KEY,
+	version_id  INTEGER NOT NULL REFERENCES versions (id),
+	position    INTEGER NOT NULL,
+	glossary_id TEXT NOT NULL,
+	key_text    TEXT NOT NULL,
+	body        TEXT NOT NULL
+);
+
+CREATE TABLE glossary_aliases (
+	glossary_item_id INTEGER NOT NULL REFERENCES glossary_items (id),
+	alias            TEXT NOT NULL
+);
+
+CREATE TABLE rule_references (
+	section_id         INTEGER NOT NULL REFERENCES sections (id),
+	referenced_rule_id TEXT NOT NULL
+);
+
+CREATE TABLE changes (
+	version_id          INTEGER NOT NULL REFERENCES versions (id),
+	previous_version_id INTEGER NOT NULL REFERENCES versions (id),
+	rule_id             TEXT NOT NULL,
+	type                TEXT NOT NULL
+);
+
+CREATE VIRTUAL TABLE search USING fts5 (
+	kind,
+	version_id UNINDEXED,
+	ref_id UNINDEXED,
+	name,
+	body
+);
+
+CREATE INDEX sections_rule_id ON sections (rule_id);
+CREATE INDEX glossary_items_glossary_id ON glossary_items (glossary_id);
+CREATE INDEX rule_references_referenced_rule_id ON rule_references (referenced_rule_id);
+CREATE INDEX changes_rule_id ON changes (rule_id);
+`
+
+// ruleReferences returns the IDs of the rules referenced in the text.
+func ruleReferences(tokenizer *parser.Tokenizer, s string) []string {
+	var out []string
+	for _, inline := range tokenizer.Tokenize(s) {
+		if inline.Kind == parser.InlineRuleRef {
+			out = append(out, inline.Target)
+		}
+	}
+
+	return out
+}
+
+// sqliteExporter writes versions of the rules into an SQLite database, each
+// version is compared against the version exported before it.
+type sqliteExporter struct {
+	tx        *sql.Tx
+	tokenizer *parser.Tokenizer
+
+	previous   *parser.Rules
+	previousID int64
+}
+
+func (e *sqliteExporter) exec(query string, args ...any) (int64, error) {
+	res, err := e.tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+func (e *sqliteExporter) exportSection(versionID int64, position int, section parser.Section) error {
+	body := strings.Join(section.Body, "\n")
+
+	sectionID, err := e.exec(
+		`INSERT INTO sections (version_id, position, rule_id, number, type, body) VALUES (?, ?, ?, ?, ?, ?)`,
+		versionID, position, section.ID, section.Number, string(section.Type), body,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, example := range section.Examples {
+		_, err = e.exec(
+			`INSERT INTO examples (section_id, position, body) VALUES (?, ?, ?)`,
+			sectionID, i, example,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, text := range append(append([]string{}, section.Body...), section.Examples...) {
+		for _, ref := range ruleReferences(e.tokenizer, text) {
+			_, err = e.exec(
+				`INSERT INTO rule_references (section_id, referenced_rule_id) VALUES (?, ?)`,
+				sectionID, ref,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = e.exec(
+		`INSERT INTO search (kind, version_id, ref_id, name, body) VALUES ('section', ?, ?, ?, ?)`,
+		versionID, sectionID, section.ID, strings.Join(append([]string{body}, section.Examples...), "\n"),
+	)
+	return err
+}
+
+func (e *sqliteExporter) exportGlossaryItem(versionID int64, position int, item parser.GlossaryItem) error {
+	itemID, err := e.exec(
+		`INSERT INTO glossary_items (version_id, position, glossary_id, key_text, body) VALUES (?, ?, ?, ?, ?)`,
+		versionID, position, item.ID, item.KeyText, item.Body,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, alias := range item.KeyParts {
+		_, err = e.exec(
+			`INSERT INTO glossary_aliases (glossary_item_id, alias) VALUES (?, ?)`,
+			itemID, alias,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = e.exec(
+		`INSERT INTO search (kind, version_id, ref_id, name, body) VALUES ('glossary', ?, ?, ?, ?)`,
+		versionID, itemID, item.KeyText, item.Body,
+	)
+	return err
+}
+
+func (e *sqliteExporter) Export(file string, rules parser.Rules) error {
+	e.tokenizer = parser.NewTokenizer(rules)
+
+	versionID, err := e.exec(
+		`INSERT INTO versions (effective_date, file) VALUES (?, ?)`,
+		rules.EffectiveDate.Format("2006-01-02"), file,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i, section := range rules.Rules {
+		err = e.exportSection(versionID, i, section)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, item := range rules.Glossary {
+		err = e.exportGlossaryItem(versionID, i, item)
+		if err != nil {
+			return err
+		}
+	}
+
+	if e.previous != nil {
+		for _, change := range parser.Diff(*e.previous, rules) {
+			_, err = e.exec(
+				`INSERT INTO changes (version_id, previous_version_id, rule_id, type) VALUES (?, ?, ?, ?)`,
+				versionID, e.previousID, change.ID, string(change.Type),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	e.previous = &rules
+	e.previousID = versionID
+
+	return nil
+}
+
+func exportSQLiteRun(cmd *cobra.Command, args []string) error {
+	err := os.Remove(exportSQLiteOut)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	db, err := sql.Open("sqlite", exportSQLiteOut)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(sqliteSchema)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	exporter := &sqliteExporter{tx: tx}
+	err = forEachRules(cmd, exportSQLiteAll, func(file string, rules parser.Rules) error {
+		cmd.Printf("exporting rules effective as of %s\n", rules.EffectiveDate.Format("2006-01-02"))
+		return exporter.Export(file, rules)
+	})
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return tx.Commit()
+}
+
+var exportSQLiteCmd = &cobra.Command{
+	Use:   "sqlite",
+	Short: "Export the rules into an SQLite database with a full-text search index",
+	Args:  cobra.NoArgs,
+	RunE:  exportSQLiteRun,
+}
+
+func init() {
+	exportCmd.AddCommand(exportSQLiteCmd)
+
+	exportSQLiteCmd.Flags().StringVarP(&exportSQLiteOut, "out", "o", "MagicCompRules.sqlite",
+		"file to write the database to, an existing file is replaced",
+	)
+	exportSQLiteCmd.Flags().BoolVar(&exportSQLiteAll, "all", false,
+		"export all of the parseable versions of the rules in the archive instead of only the current rules",
+	)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+var exportTextOut string
+
+// writeText writes the rules as plain text in the same layout as the official
+// .txt file, but normalized and without the table of contents.
+func writeText(w io.Writer, rules parser.Rules) {
+	fmt.Fprintf(w, "Magic: The Gathering Comprehensive Rules\n\n")
+	fmt.Fprintf(w, "These rules are effective as of %s.\n\n", rules.EffectiveDate.Format("January 2, 2006"))
+
+	if len(rules.Introduction) > 0 {
+		fmt.Fprintf(w, "Introduction\n\n")
+		for _, paragraph := range rules.Introduction {
+			fmt.Fprintf(w, "%s\n\n", paragraph)
+		}
+	}
+
+	for _, section := range rules.Rules {
+		for i, line := range section.Body {
+			if i == 0 {
+				fmt.Fprintf(w, "%s %s\n", section.Number, line)
+			} else {
+				fmt.Fprintf(w, "%s\n", line)
+			}
+		}
+
+		for _, example := range section.Examples {
+			fmt.Fprintf(w, "Example: %s\n", example)
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "Glossary\n\n")
+	for _, item := range rules.Glossary {
+		fmt.Fprintf(w, "%s\n%s\n\n", item.KeyText, item.Body)
+	}
+
+	fmt.Fprintf(w, "Credits\n\n")
+	for _, credit := range rules.Credits.Paragraphs {
+		fmt.Fprintf(w, "%s\n\n", credit)
+	}
+}
+
+func exportTextRun(cmd *cobra.Command, args []string) error {
+	rules, err := openAndParseRules(cmd)
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("writing text to %q\n", exportTextOut)
+	return renderToFile(exportTextOut, func(w io.Writer) error {
+		writeText(w, rules)
+		return nil
+	})
+}
+
+var exportTextCmd = &cobra.Command{
+	Use:   "text",
+	Short: "Export the current rules as normalized plain text",
+	Args:  cobra.NoArgs,
+	RunE:  exportTextRun,
+}
+
+func init() {
+	exportCmd.AddCommand(exportTextCmd)
+
+	exportTextCmd.Flags().StringVarP(&exportTextOut, "out", "o", "MagicCompRules.normalized.txt",
+		"file to write the text to",
+	)
+}
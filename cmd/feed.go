@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/archiver"
+	"github.com/xremming/rulesraker/parser"
+)
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+	Summary atomText `xml:"summary"`
+	Content atomText `xml:"content"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  string      `xml:"author>name"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// maxFeedChanges is the maximum number of changed rules listed in the content
+// of a single feed entry.
+const maxFeedChanges = 100
+
+func feedEntryID(date archiver.JSONDate) string {
+	return fmt.Sprintf("tag:rulesraker.com,%s:%s", time.Time(date).Format("2006"), date)
+}
+
+func summarizeChanges(changes []parser.Change) (string, string) {
+	counts := make(map[parser.ChangeType]int)
+	for _, change := range changes {
+		counts[change.Type]++
+	}
+
+	summary := fmt.Sprintf(
+		"Added %d, removed %d and modified %d rules.",
+		counts[parser.Added], counts[parser.Removed], counts[parser.Modified],
+	)
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "<p>%s</p>\n<ul>\n", html.EscapeString(summary))
+	for i, change := range changes {
+		if i == maxFeedChanges {
+			fmt.Fprintf(&content, "<li>and %d more</li>\n", len(changes)-maxFeedChanges)
+			break
+		}
+
+		fmt.Fprintf(&content, "<li>%s %s</li>\n", change.Type, html.EscapeString(change.ID))
+	}
+	content.WriteString("</ul>")
+
+	return summary, content.String()
+}
+
+// newFeedEntry creates the feed entry of a release, previous is nil when the
+// changes of the release are not known.
+func newFeedEntry(metadata archiver.Metadata, date archiver.JSONDate, previous, current *parser.Rules) atomEntry {
+	link := rulesURL
+	if file, ok := metadata.RuleFile(date, "txt"); ok && file.URL != nil {
+		link = *file.URL
+	}
+
+	entry := atomEntry{
+		ID:      feedEntryID(date),
+		Title:   fmt.Sprintf("Comprehensive Rules released on %s", time.Time(date).Format("January 2, 2006")),
+		Updated: time.Time(date).Format(time.RFC3339),
+		Link:    atomLink{Href: link, Rel: "alternate"},
+	}
+	if current != nil {
+		entry.Title = fmt.Sprintf("Comprehensive Rules effective as of %s", current.EffectiveDate.Format("January 2, 2006"))
+	}
+
+	if previous == nil || current == nil {
+		entry.Summary = atomText{"text", "A new version of the comprehensive rules was released."}
+		entry.Content = atomText{"text", "The changes of this version are not available as it or the version before it could not be parsed."}
+		return entry
+	}
+
+	summary, content := summarizeChanges(parser.Diff(*previous, *current))
+	entry.Summary = atomText{"text", summary}
+	entry.Content = atomText{"html", content}
+
+	return entry
+}
+
+// newFeed creates a feed with one entry for each known release in the archive,
+// newest first. Releases whose rules can't be parsed are logged and still
+// listed but without a summary of the changes.
+func newFeed(cmd *cobra.Command, metadata archiver.Metadata, parse func(date archiver.JSONDate) (parser.Rules, error)) atomFeed {
+	feed := atomFeed{
+		ID:     siteURL + "/",
+		Title:  "Rulesraker - Magic: the Gathering Comprehensive Rules updates",
+		Author: "Rulesraker",
+		Links: []atomLink{
+			{Href: siteURL + "/feed.xml", Rel: "self", Type: "application/atom+xml"},
+			{Href: siteURL + "/", Rel: "alternate", Type: "text/html"},
+		},
+	}
+
+	var previous *parser.Rules
+	for _, date := range metadata.KnownExistingDates {
+		var current *parser.Rules
+		rules, err := parse(date)
+		if err != nil {
+			cmd.Printf("listing %s in the feed without changes as it could not be parsed: %v\n", date, err)
+		} else {
+			current = &rules
+		}
+
+		feed.Entries = append(feed.Entries, newFeedEntry(metadata, date, previous, current))
+		previous = current
+	}
+
+	slices.Reverse(feed.Entries)
+	if len(feed.Entries) > 0 {
+		feed.Updated = feed.Entries[0].Updated
+	}
+
+	return feed
+}
+
+func renderFeed(w io.Writer, feed atomFeed) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	err = enc.Encode(feed)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
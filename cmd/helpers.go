@@ -3,18 +3,21 @@ package cmd
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"html/template"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"reflect"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/archiver"
 	"github.com/xremming/rulesraker/parser"
 )
 
@@ -41,16 +44,28 @@ func (jd FlagDate) String() string {
 	return time.Time(jd).Format("2006-01-02")
 }
 
+func rulesPath() string {
+	return filepath.Join(dataDir, "MagicCompRules.txt")
+}
+
 func openAndParseRules(cmd *cobra.Command) (parser.Rules, error) {
 	cmd.Println("opening rules text")
-	fp, err := os.Open(filepath.Join(dataDir, "MagicCompRules.txt"))
+	fp, err := os.Open(rulesPath())
 	if err != nil {
 		return parser.Rules{}, err
 	}
 	defer fp.Close()
 
 	cmd.Println("parsing rules")
-	rules, err := parser.Parse(fp)
+	rules, diagnostics, err := parser.ParseWithOptions(fp, parser.ParseOptions{Lenient: lenient})
+	for _, d := range diagnostics {
+		cmd.Printf("%s:%v\n", rulesPath(), d)
+	}
+	if err != nil {
+		return parser.Rules{}, err
+	}
+
+	err = detectCards(&rules)
 	if err != nil {
 		return parser.Rules{}, err
 	}
@@ -58,6 +73,120 @@ func openAndParseRules(cmd *cobra.Command) (parser.Rules, error) {
 	return rules, nil
 }
 
+func readMetadata() (archiver.Metadata, error) {
+	fp, err := os.Open(filepath.Join(archiveDir, "metadata.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return archiver.Metadata{}, fmt.Errorf("metadata.json not found in %s", archiveDir)
+		}
+
+		return archiver.Metadata{}, err
+	}
+	defer fp.Close()
+
+	var metadata archiver.Metadata
+	err = json.NewDecoder(fp).Decode(&metadata)
+	if err != nil {
+		return archiver.Metadata{}, err
+	}
+
+	return metadata, nil
+}
+
+// openAndParseArchiveRules parses the archived .txt rules file of the date.
+func openAndParseArchiveRules(metadata archiver.Metadata, date archiver.JSONDate) (parser.Rules, error) {
+	file, ok := metadata.RuleFile(date, "txt")
+	if !ok {
+		return parser.Rules{}, fmt.Errorf("no txt file archived for %s", date)
+	}
+
+	fp, err := os.Open(filepath.Join(archiveDir, file.File))
+	if err != nil {
+		return parser.Rules{}, err
+	}
+	defer fp.Close()
+
+	rules, _, err := parser.ParseWithOptions(fp, parser.ParseOptions{Lenient: lenient})
+	if err != nil {
+		return parser.Rules{}, err
+	}
+
+	return rules, detectCards(&rules)
+}
+
+// openArchiveRulesOn parses the archived rules in effect on the date.
+func openArchiveRulesOn(cmd *cobra.Command, date time.Time) (parser.Rules, error) {
+	metadata, err := readMetadata()
+	if err != nil {
+		return parser.Rules{}, err
+	}
+
+	res, err := metadata.RulesOn(archiveDir, date, parser.ParseOptions{Lenient: lenient})
+	if err != nil {
+		return parser.Rules{}, err
+	}
+
+	cmd.Printf("rules released on %s were in effect on %s\n", res.Release, date.Format("2006-01-02"))
+
+	if res.Missing != nil {
+		return parser.Rules{}, fmt.Errorf("rules released on %s are known to be missing from the archive, see %s", res.Release, res.Missing.Source)
+	}
+
+	if res.Err != nil {
+		return parser.Rules{}, fmt.Errorf("rules released on %s could not be parsed: %w", res.Release, res.Err)
+	}
+
+	cmd.Printf("parsed %s effective %s\n", res.File, res.Rules.EffectiveDate.Format("2006-01-02"))
+
+	rules := *res.Rules
+	return rules, detectCards(&rules)
+}
+
+// forEachRules calls fn with the current rules, and when all is set first with
+// every parseable version of the rules in the archive, in the order of their
+// effective dates. Archived versions which fail to parse or have the same
+// effective date as an earlier version or the current rules are skipped. The
+// file is relative to the data or archive directory.
+func forEachRules(cmd *cobra.Command, all bool, fn func(file string, rules parser.Rules) error) error {
+	current, err := openAndParseRules(cmd)
+	if err != nil {
+		return err
+	}
+
+	if all {
+		metadata, err := readMetadata()
+		if err != nil {
+			return err
+		}
+
+		seen := map[time.Time]bool{current.EffectiveDate: true}
+		for _, date := range metadata.KnownExistingDates {
+			file, ok := metadata.RuleFile(date, "txt")
+			if !ok {
+				continue
+			}
+
+			rules, err := openAndParseArchiveRules(metadata, date)
+			if err != nil {
+				cmd.Printf("skipping %s as it could not be parsed\n", date)
+				continue
+			}
+
+			if seen[rules.EffectiveDate] {
+				continue
+			}
+			seen[rules.EffectiveDate] = true
+
+			err = fn(file.File, rules)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return fn(filepath.Base(rulesPath()), current)
+}
+
 func makeCSP() (string, string) {
 	bytes := make([]byte, 12)
 	_, err := rand.Read(bytes)
@@ -70,7 +199,7 @@ func makeCSP() (string, string) {
 	cspLines := []string{
 		"default-src 'self'",
 		fmt.Sprintf("script-src 'self' 'nonce-%s' https://static.cloudflareinsights.com", nonce),
-		"img-src 'self' https://svgs.scryfall.io https://cards.scryfall.io",
+		"img-src 'self' https://cards.scryfall.io",
 		"connect-src 'self' https://api.scryfall.com https://cloudflareinsights.com",
 		"object-src 'none'",
 		"child-src 'none'",
@@ -87,10 +216,6 @@ func newlineToBR(s string) template.HTML {
 	return template.HTML(strings.ReplaceAll(s, "\n", "<br>"))
 }
 
-func startsWith(prefix, s string) bool {
-	return strings.HasPrefix(s, prefix)
-}
-
 func asString(s any) string {
 	return reflect.ValueOf(s).String()
 }
@@ -99,104 +224,255 @@ func lower(s any) string {
 	return strings.ToLower(asString(s))
 }
 
-var linkifyRegexp = regexp.MustCompile(`(?i)([a-z\.]+\.com[a-z-\/]*)`)
+// documentRefURL returns the URL a reference to another document links to.
+func documentRefURL(target string) string {
+	ref, err := parser.ParseReference(target)
+	if err != nil {
+		return ""
+	}
+
+	return ref.URL()
+}
+
+// htmlInlines renders the inline spans as HTML, symbols are replaced with
+// images by the symbol replacer. Card names link to Scryfall and are previewed
+// on hover by cards.js.
+func htmlInlines(inlines []parser.Inline, symbolReplacer *strings.Replacer) template.HTML {
+	var out strings.Builder
+	for _, inline := range inlines {
+		text := html.EscapeString(inline.Text)
+
+		switch inline.Kind {
+		case parser.InlineSymbol:
+			out.WriteString(symbolReplacer.Replace(text))
+		case parser.InlineRuleRef:
+			fmt.Fprintf(&out, `<a href="#%s">%s</a>`, html.EscapeString(inline.Target), text)
+		case parser.InlineGlossaryTerm:
+			fmt.Fprintf(&out, `<a href="#%s">%s</a>`, html.EscapeString(glossaryAnchor(inline.Target)), text)
+		case parser.InlineLink:
+			fmt.Fprintf(&out, `<a href="%s" target="_blank">%s</a>`, html.EscapeString(inline.Target), text)
+		case parser.InlineDocumentRef:
+			fmt.Fprintf(&out, `<a href="%s" target="_blank">%s</a>`, html.EscapeString(documentRefURL(inline.Target)), text)
+		case parser.InlineCard:
+			fmt.Fprintf(&out, `<a class="card-name" href="%s" target="_blank" data-card="%s">%s</a>`,
+				html.EscapeString(scryfallCardURL(inline.Target)), html.EscapeString(inline.Target), text)
+		default:
+			out.WriteString(text)
+		}
+	}
 
-func linkify(s any) template.HTML {
-	return template.HTML(linkifyRegexp.ReplaceAllString(asString(s), `<a href="https://$1" target="_blank">$0</a>`))
+	return template.HTML(out.String())
 }
 
-var numberRegexp = regexp.MustCompile(`(\d{3})(\.((\d+)(\w+)?)(–(\d+|\w)+)?)?`)
+// indexData is the data the index page is rendered with. The sitemap and the
+// structured data of the page are derived from the same data.
+type indexData struct {
+	Title         string
+	Description   string
+	URL           string
+	CSP           string
+	Nonce         string
+	RulesURL      string
+	EffectiveDate time.Time
+	Rules         []parser.Section
+	Glossary      []parser.GlossaryItem
+	Keywords      []parser.Keyword
+	Credits       []string
+}
+
+func newIndexData(rules parser.Rules) indexData {
+	return indexData{
+		Title:         "Rulesraker - Magic: the Gathering Comprehensive Rules",
+		Description:   "A fast and easy interface to Magic: the Gathering's Comprehensive Rules.",
+		URL:           siteURL + "/",
+		RulesURL:      rulesURL,
+		EffectiveDate: rules.EffectiveDate,
+		Rules:         rules.Rules,
+		Glossary:      rules.Glossary,
+		Keywords:      rules.Keywords,
+		Credits:       rules.Credits.Paragraphs,
+	}
+}
 
-func parseNumber(rule string) string {
-	matches := numberRegexp.FindStringSubmatch(rule)
+// IsKeyword reports whether the rule is the rule of a keyword, used to list
+// only the keywords out of all of the rules in the table of contents.
+func (d indexData) IsKeyword(id string) bool {
+	return parser.Rules{Keywords: d.Keywords}.IsKeyword(id)
+}
 
-	major := matches[1]
-	minor := matches[4]
-	letter := matches[5]
+func renderIndex(w io.Writer, data indexData, symbolReplacer *strings.Replacer) error {
+	tokenizer := parser.NewTokenizer(parser.Rules{Rules: data.Rules, Glossary: data.Glossary})
 
-	if minor == "" && letter == "" {
-		return fmt.Sprintf("%v.", major)
+	tmpl, err := template.New("").
+		Funcs(template.FuncMap{
+			"formatTime":  formatTime,
+			"newlineToBR": newlineToBR,
+			"lower":       lower,
+			"lines": func(s string) []string {
+				return strings.Split(s, "\n")
+			},
+			"glossaryAnchor": glossaryAnchor,
+			"inlines": func(s string) template.HTML {
+				return htmlInlines(tokenizer.Tokenize(s), symbolReplacer)
+			},
+		}).
+		ParseFS(os.DirFS(templateDir), "index.html", "rule.html")
+	if err != nil {
+		return err
 	}
 
-	if letter == "" {
-		return fmt.Sprintf("%v.%v.", major, minor)
+	data.Nonce, data.CSP = makeCSP()
+
+	return tmpl.ExecuteTemplate(w, "index.html", data)
+}
+
+// documentTitles are the titles of the documents rendered next to the
+// comprehensive rules.
+var documentTitles = map[parser.DocumentKind]string{
+	parser.MTR: "Magic: The Gathering Tournament Rules",
+	parser.IPG: "Magic: The Gathering Infraction Procedure Guide",
+}
+
+// documentData is the data the pages of the tournament rules and the
+// infraction procedure guide are rendered with.
+type documentData struct {
+	Name      string
+	Title     string
+	URL       string
+	CSP       string
+	Nonce     string
+	SourceURL string
+	Document  parser.Document
+}
+
+func newDocumentData(doc parser.Document) documentData {
+	return documentData{
+		Name:      documentTitles[doc.Kind],
+		Title:     "Rulesraker - " + documentTitles[doc.Kind],
+		URL:       parser.Reference{Document: doc.Kind}.URL(),
+		SourceURL: parser.DocumentURLs[doc.Kind],
+		Document:  doc,
 	}
+}
 
-	return fmt.Sprintf("%v.%v%v", major, minor, letter)
+// IsTopLevel reports whether the section is a top level section of the
+// document, which are rendered as parts.
+func (d documentData) IsTopLevel(id string) bool {
+	return strings.HasSuffix(id, ".")
 }
 
-var sectionRefRegexp = regexp.MustCompile(`section (\d)`)
+// documentInlines renders the paragraph of the document as HTML with its
+// references turned into links, references to the document itself link to
+// the section on the same page.
+func documentInlines(kind parser.DocumentKind, s string) template.HTML {
+	var (
+		out  strings.Builder
+		last int
+	)
+	for _, span := range parser.FindReferences(kind, s) {
+		href := span.URL()
+		if span.Document == kind {
+			href = "#" + span.ID
+		}
 
-func ruleLinks(s any) template.HTML {
-	text := numberRegexp.ReplaceAllStringFunc(asString(s), func(s string) string {
-		return fmt.Sprintf(`<a href="#%s">%s</a>`, parseNumber(s), s)
-	})
+		out.WriteString(html.EscapeString(s[last:span.Start]))
+		fmt.Fprintf(&out, `<a href="%s">%s</a>`, html.EscapeString(href), html.EscapeString(s[span.Start:span.End]))
+		last = span.End
+	}
+	out.WriteString(html.EscapeString(s[last:]))
 
-	return template.HTML(sectionRefRegexp.ReplaceAllString(text, `<a href="#$1.">$0</a>`))
+	return template.HTML(out.String())
 }
 
-func renderIndex(w io.Writer, rules parser.Rules, symbolReplacer *strings.Replacer) error {
+func renderDocument(w io.Writer, data documentData) error {
 	tmpl, err := template.New("").
 		Funcs(template.FuncMap{
-			"formatTime":  formatTime,
-			"newlineToBR": newlineToBR,
-			"startsWith":  startsWith,
-			"lower":       lower,
-			"linkify":     linkify,
-			"ruleLinks":   ruleLinks,
-			"replaceSymbols": func(s any) template.HTML {
-				return template.HTML(symbolReplacer.Replace(asString(s)))
+			"formatTime": formatTime,
+			"inlines": func(s string) template.HTML {
+				return documentInlines(data.Document.Kind, s)
 			},
 		}).
-		ParseFS(os.DirFS(templateDir), "*.html")
+		ParseFS(os.DirFS(templateDir), "document.html")
+	if err != nil {
+		return err
+	}
+
+	data.Nonce, data.CSP = makeCSP()
+
+	return tmpl.ExecuteTemplate(w, "document.html", data)
+}
+
+// renderDocumentPage renders the page of the document parsed with
+// parse-document into the output directory. Documents which have not been
+// parsed are skipped.
+func renderDocumentPage(cmd *cobra.Command, kind parser.DocumentKind) error {
+	path := filepath.Join(dataDir, documentSources[kind].name+".json")
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		cmd.Printf("not rendering %s as %s does not exist\n", kind, path)
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	nonce, csp := makeCSP()
+	var doc parser.Document
+	err = json.Unmarshal(content, &doc)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
 
-	return tmpl.ExecuteTemplate(w, "index.html", map[string]any{
-		"Title":         "Rulesraker - Magic: the Gathering Comprehensive Rules",
-		"Description":   "A fast and easy interface to Magic: the Gathering's Comprehensive Rules.",
-		"CSP":           csp,
-		"Nonce":         nonce,
-		"RulesURL":      rulesURL,
-		"EffectiveDate": rules.EffectiveDate,
-		"Rules":         rules.Rules,
-		"Glossary":      rules.Glossary,
-		"Credits":       rules.Credits,
+	page := parser.DocumentPages[kind]
+	cmd.Printf("rendering %s\n", page)
+	return renderToFile(filepath.Join(outputDir, page), func(w io.Writer) error {
+		return renderDocument(w, newDocumentData(doc))
 	})
 }
 
-func copyRecursive(cmd *cobra.Command, from fs.FS, to string) error {
-	return fs.WalkDir(from, ".", func(path string, d fs.DirEntry, _ error) error {
-		if d.IsDir() {
-			return nil
-		}
+// isGeneratedFile reports whether the file in the output directory is rendered
+// by the build and thus should not be copied from the public directory.
+func isGeneratedFile(path string) bool {
+	switch path {
+	case "index.html", "mtr.html", "ipg.html", "sitemap.xml", "robots.txt", "feed.xml":
+		return true
+	}
 
-		if path == "index.html" {
-			cmd.Println("skipping index.html from the public directory")
-			return nil
-		}
+	return false
+}
 
-		cmd.Printf("copying %q from public directory to the output directory\n", path)
-		inp, err := from.Open(path)
-		if err != nil {
-			return err
-		}
-		defer inp.Close()
+func copyFile(cmd *cobra.Command, from fs.FS, path, to string) error {
+	if isGeneratedFile(path) {
+		cmd.Printf("skipping generated file %q\n", path)
+		return nil
+	}
 
-		out, err := os.Create(filepath.Join(to, path))
-		if err != nil {
-			return err
-		}
-		defer out.Close()
+	cmd.Printf("copying %q to %q\n", path, to)
+	inp, err := from.Open(path)
+	if err != nil {
+		return err
+	}
+	defer inp.Close()
 
-		_, err = io.Copy(out, inp)
-		if err != nil {
-			return err
+	out, err := os.Create(filepath.Join(to, path))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, inp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func copyRecursive(cmd *cobra.Command, from fs.FS, to string) error {
+	return fs.WalkDir(from, ".", func(path string, d fs.DirEntry, _ error) error {
+		if d.IsDir() {
+			return nil
 		}
 
-		return nil
+		return copyFile(cmd, from, path, to)
 	})
 }
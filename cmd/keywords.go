@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+var keywordsKind string
+
+func keywordsRun(cmd *cobra.Command, args []string) error {
+	rules, err := openParsedRules()
+	if err != nil {
+		return err
+	}
+
+	f := newTerminalFormatter(rules)
+	w := cmd.OutOrStdout()
+
+	if len(args) == 0 {
+		for _, keyword := range rules.Keywords {
+			if keywordsKind != "" && !strings.EqualFold(keywordsKind, string(keyword.Kind)) {
+				continue
+			}
+
+			fmt.Fprintf(w, "%s %s\n", f.style(keyword.RuleID, ansiBold), keyword.Name)
+		}
+
+		return nil
+	}
+
+	for i, name := range args {
+		var keyword *parser.Keyword
+		for _, k := range rules.Keywords {
+			if strings.EqualFold(k.Name, name) {
+				keyword = &k
+				break
+			}
+		}
+		if keyword == nil {
+			return fmt.Errorf("keyword %q not found", name)
+		}
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "%s %s\n", f.style(keyword.RuleID, ansiBold), f.style(keyword.Name, ansiBold))
+		fmt.Fprintf(w, "Keyword %s\n", strings.ToLower(string(keyword.Kind)))
+		if keyword.Reminder != "" {
+			fmt.Fprintf(w, "%s\n", f.style(f.Text(keyword.Reminder), ansiItalic))
+		}
+		if keyword.Redundant {
+			fmt.Fprintf(w, "Multiple instances are redundant.\n")
+		}
+		if len(keyword.Subrules) > 0 {
+			fmt.Fprintf(w, "Rules: %s\n", strings.Join(keyword.Subrules, ", "))
+		}
+		if keyword.GlossaryID != "" {
+			fmt.Fprintf(w, "Glossary: %s\n", keyword.GlossaryID)
+		}
+	}
+
+	return nil
+}
+
+var keywordsCmd = &cobra.Command{
+	Use:   "keywords [name]...",
+	Short: "List the keyword actions and abilities or show the details of some of them",
+	Example: `  rulesraker keywords --kind ability
+  rulesraker keywords deathtouch`,
+	RunE: keywordsRun,
+}
+
+func init() {
+	rootCmd.AddCommand(keywordsCmd)
+
+	keywordsCmd.Flags().StringVar(&keywordsKind, "kind", "",
+		"list only keywords of the kind, action or ability",
+	)
+	keywordsCmd.Flags().BoolVar(&noColor, "no-color", false,
+		"disable colored output",
+	)
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
 )
 
 func parseRun(cmd *cobra.Command, args []string) error {
@@ -28,7 +29,22 @@ func parseRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return nil
+	return writeJSONSchema()
+}
+
+// writeJSONSchema writes the JSON Schema of the format of the parsed rules next
+// to them.
+func writeJSONSchema() error {
+	out, err := os.Create(filepath.Join(dataDir, "MagicCompRules.schema.json"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(parser.JSONSchema())
 }
 
 var parseCmd = &cobra.Command{
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+func parseDocumentRun(cmd *cobra.Command, args []string) error {
+	document := parser.DocumentKind(strings.ToUpper(args[0]))
+	source, ok := documentSources[document]
+	if !ok || document == parser.CR {
+		return fmt.Errorf("unknown document %q, expected MTR or IPG", args[0])
+	}
+
+	path := filepath.Join(dataDir, source.name+".txt")
+	if len(args) > 1 {
+		path = args[1]
+	}
+
+	cmd.Printf("opening %s text %s\n", document, path)
+	fp, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+
+	cmd.Printf("parsing %s\n", document)
+	doc, err := parser.ParseDocument(fp, document)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	out, err := os.Create(filepath.Join(dataDir, source.name+".json"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(doc)
+}
+
+var parseDocumentCmd = &cobra.Command{
+	Use:   "parse-document <MTR|IPG> [text file]",
+	Short: "Parse the text of the tournament rules or the infraction procedure guide into a .json file",
+	Long: `Parse the text of the tournament rules or the infraction procedure guide into a
+.json file. The documents are published only as PDFs, the text has to be
+extracted first, e.g. with pdftotext. By default the text is read from the
+data directory next to the PDF downloaded by scrape.`,
+	Example: `  pdftotext data/MagicTournamentRules.pdf && rulesraker parse-document mtr
+  rulesraker parse-document ipg ipg.txt`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: parseDocumentRun,
+}
+
+func init() {
+	rootCmd.AddCommand(parseDocumentCmd)
+}
@@ -12,6 +12,8 @@ import (
 var (
 	dataDir    string
 	archiveDir string
+	lenient    bool
+	cardsFile  string
 )
 
 var rootCmd = &cobra.Command{
@@ -35,6 +37,12 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&archiveDir, "archive-dir", "a", "archive",
 		"directory to store stores the archived rules",
 	)
+	rootCmd.PersistentFlags().BoolVar(&lenient, "lenient", false,
+		"use the rules which could be parsed even when errors are found in them",
+	)
+	rootCmd.PersistentFlags().StringVar(&cardsFile, "cards", "",
+		"Scryfall bulk data file of the cards to detect in the rules, downloaded by cards sync",
+	)
 }
 
 func Execute() {
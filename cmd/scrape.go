@@ -8,15 +8,35 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
 )
 
-const rulesURL = "https://magic.wizards.com/en/rules"
+const siteURL = parser.SiteURL
+
+var rulesURL = parser.DocumentURLs[parser.CR]
 
 var ruleLinksRegexp = regexp.MustCompile(`"([^"]+\.(docx|pdf|txt))"`)
 
-func download(url, ext string) error {
+// documentSource is where the latest version of a document is scraped from.
+// The first group of links is the URL of a file and the second its format.
+type documentSource struct {
+	name     string
+	indexURL string
+	links    *regexp.Regexp
+}
+
+var documentSources = map[parser.DocumentKind]documentSource{
+	parser.CR:  {"MagicCompRules", parser.DocumentURLs[parser.CR], ruleLinksRegexp},
+	parser.MTR: {"MagicTournamentRules", parser.DocumentURLs[parser.MTR], regexp.MustCompile(`(?i)"([^"]+_MTR_[^"]*_EN\.(pdf))"`)},
+	parser.IPG: {"InfractionProcedureGuide", parser.DocumentURLs[parser.IPG], regexp.MustCompile(`(?i)"([^"]+_IPG_[^"]*_EN\.(pdf))"`)},
+}
+
+var scrapeDocuments []string
+
+func download(url, name, ext string) error {
 	resp, err := http.DefaultClient.Get(url)
 	if err != nil {
 		return err
@@ -27,7 +47,7 @@ func download(url, ext string) error {
 		return fmt.Errorf("downloading %q returned a non 200 status code", url)
 	}
 
-	fp, err := os.CreateTemp(os.TempDir(), fmt.Sprintf("MagicCompRules_*.%s", ext))
+	fp, err := os.CreateTemp(os.TempDir(), fmt.Sprintf("%s_*.%s", name, ext))
 	if err != nil {
 		return err
 	}
@@ -42,12 +62,12 @@ func download(url, ext string) error {
 		return err
 	}
 
-	return os.Rename(fp.Name(), filepath.Join(dataDir, fmt.Sprintf("MagicCompRules.%s", ext)))
+	return os.Rename(fp.Name(), filepath.Join(dataDir, fmt.Sprintf("%s.%s", name, ext)))
 }
 
-func scrapeRun(cmd *cobra.Command, args []string) error {
-	cmd.Printf("getting rules index page %q\n", rulesURL)
-	resp, err := http.DefaultClient.Get(rulesURL)
+func scrapeDocument(cmd *cobra.Command, source documentSource) error {
+	cmd.Printf("getting rules index page %q\n", source.indexURL)
+	resp, err := http.DefaultClient.Get(source.indexURL)
 	if err != nil {
 		return err
 	}
@@ -60,16 +80,16 @@ func scrapeRun(cmd *cobra.Command, args []string) error {
 
 	toDownload := make(map[string]string)
 
-	matches := ruleLinksRegexp.FindAllStringSubmatch(string(body), -1)
+	matches := source.links.FindAllStringSubmatch(string(body), -1)
 	for _, match := range matches {
 		ext := match[2]
 		url := match[1]
 
-		_, ok := toDownload[ext]
-		if ok {
+		other, ok := toDownload[ext]
+		if ok && other != url {
 			return fmt.Errorf(
 				"format %s is defined multiple times on %s, not sure which to download",
-				ext, rulesURL,
+				ext, source.indexURL,
 			)
 		}
 
@@ -79,7 +99,7 @@ func scrapeRun(cmd *cobra.Command, args []string) error {
 	var errDownload error
 	for ext, url := range toDownload {
 		cmd.Printf("downloading %4s from %q\n", ext, url)
-		errDownload = errors.Join(errDownload, download(url, ext))
+		errDownload = errors.Join(errDownload, download(url, source.name, ext))
 	}
 
 	if errDownload != nil {
@@ -89,14 +109,34 @@ func scrapeRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func scrapeRun(cmd *cobra.Command, args []string) error {
+	var errScrape error
+	for _, document := range scrapeDocuments {
+		source, ok := documentSources[parser.DocumentKind(strings.ToUpper(document))]
+		if !ok {
+			return fmt.Errorf("unknown document %q", document)
+		}
+
+		errScrape = errors.Join(errScrape, scrapeDocument(cmd, source))
+	}
+
+	return errScrape
+}
+
 var scrapeCmd = &cobra.Command{
 	Use:     "scrape",
 	Aliases: []string{"s"},
-	Short:   "Scrape the latest comprehensive rules from wizards.com",
-	Args:    cobra.NoArgs,
-	RunE:    scrapeRun,
+	Short:   "Scrape the latest comprehensive rules and other rules documents from wizards.com",
+	Example: `  rulesraker scrape
+  rulesraker scrape --document cr --document mtr --document ipg`,
+	Args: cobra.NoArgs,
+	RunE: scrapeRun,
 }
 
 func init() {
 	rootCmd.AddCommand(scrapeCmd)
+
+	scrapeCmd.Flags().StringSliceVar(&scrapeDocuments, "document", []string{string(parser.CR)},
+		"documents to scrape, CR for the comprehensive rules, MTR for the tournament rules and IPG for the infraction procedure guide",
+	)
 }
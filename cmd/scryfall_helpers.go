@@ -2,9 +2,22 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
+	"sync"
+
+	"github.com/xremming/rulesraker/parser"
+)
+
+const (
+	scryfallSymbologyURL = "https://api.scryfall.com/symbology"
+	scryfallBulkDataURL  = "https://api.scryfall.com/bulk-data/oracle-cards"
 )
 
 type CardSymbolList struct {
@@ -16,29 +29,187 @@ type CardSymbol struct {
 	SVGURI string `json:"svg_uri"`
 }
 
-func getSymbolsReplacer() (*strings.Replacer, error) {
-	resp, err := http.DefaultClient.Get("https://api.scryfall.com/symbology")
+// FileName returns the name of the file the symbol's SVG is stored as in the
+// local symbol cache, e.g. "W.svg" for "{W}".
+func (cs CardSymbol) FileName() (string, error) {
+	u, err := url.Parse(cs.SVGURI)
+	if err != nil {
+		return "", err
+	}
+
+	name := path.Base(u.Path)
+	if path.Ext(name) != ".svg" {
+		return "", fmt.Errorf("symbol %s has an unexpected svg uri %q", cs.Symbol, cs.SVGURI)
+	}
+
+	return name, nil
+}
+
+func symbologyPath() string {
+	return filepath.Join(dataDir, "symbology.json")
+}
+
+func symbolsDir() string {
+	return filepath.Join(dataDir, "symbols")
+}
+
+// scryfallGet does a GET request against Scryfall with the headers their API
+// documentation requires from all clients.
+func scryfallGet(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+
+	req.Header.Set("User-Agent", "rulesraker/1.0")
+	req.Header.Set("Accept", "application/json;q=0.9,*/*;q=0.8")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("downloading %q returned a non 200 status code", url)
+	}
+
+	return resp, nil
+}
+
+// readSymbology reads the symbology cached by the symbols sync command.
+func readSymbology() (CardSymbolList, error) {
+	fp, err := os.Open(symbologyPath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return CardSymbolList{}, fmt.Errorf(
+				"symbology not found in %s, run `rulesraker symbols sync` first", dataDir,
+			)
+		}
+
+		return CardSymbolList{}, err
+	}
+	defer fp.Close()
 
 	var data CardSymbolList
+	err = json.NewDecoder(fp).Decode(&data)
+	if err != nil {
+		return CardSymbolList{}, err
+	}
+
+	return data, nil
+}
 
-	dec := json.NewDecoder(resp.Body)
+// newSymbolsReplacer returns a replacer which replaces symbols such as {W}
+// with the result of format, called with the symbol and the name of the file
+// of the symbol's SVG.
+func newSymbolsReplacer(symbols CardSymbolList, format func(symbol, fileName string) string) (*strings.Replacer, error) {
+	replace := make([]string, 0, 2*len(symbols.Data))
+	for _, cardSymbol := range symbols.Data {
+		fileName, err := cardSymbol.FileName()
+		if err != nil {
+			return nil, err
+		}
 
-	err = dec.Decode(&data)
+		replace = append(replace, cardSymbol.Symbol, format(cardSymbol.Symbol, fileName))
+	}
+
+	return strings.NewReplacer(replace...), nil
+}
+
+// getSymbolsReplacer returns a replacer which turns symbols such as {W} into
+// images pointing to the symbol's SVG under urlPrefix.
+func getSymbolsReplacer(symbols CardSymbolList, urlPrefix string) (*strings.Replacer, error) {
+	return newSymbolsReplacer(symbols, func(symbol, fileName string) string {
+		return fmt.Sprintf(
+			`<img class="symbol" title="%v" alt="%v" src="%v">`,
+			symbol, symbol, path.Join(urlPrefix, fileName),
+		)
+	})
+}
+
+// ScryfallBulkData is the description of a bulk data file of Scryfall.
+type ScryfallBulkData struct {
+	DownloadURI string `json:"download_uri"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// ScryfallCard is a card of the Scryfall bulk data, only the fields needed to
+// detect the names of the cards in the rules are decoded.
+type ScryfallCard struct {
+	Name      string
+	Layout    string
+	CardFaces []struct {
+		Name string
+	} `json:"card_faces"`
+}
+
+// nonCardLayouts are the layouts of the bulk data which are not cards
+// referred to by name in the rules.
+var nonCardLayouts = map[string]bool{
+	"token":              true,
+	"double_faced_token": true,
+	"emblem":             true,
+	"art_series":         true,
+}
+
+// scryfallCardURL returns the URL of the search for the card on Scryfall.
+func scryfallCardURL(name string) string {
+	return "https://scryfall.com/search?q=" + url.QueryEscape(`!"`+name+`"`)
+}
+
+// readCardNames reads the names of the cards from the Scryfall bulk data
+// downloaded by the cards sync command. Cards with multiple faces are named by
+// each of their faces.
+func readCardNames() ([]string, error) {
+	fp, err := os.Open(cardsFile)
 	if err != nil {
 		return nil, err
 	}
+	defer fp.Close()
 
-	replace := make([]string, 0, 2*len(data.Data))
-	for _, cardSymbol := range data.Data {
-		replace = append(replace, cardSymbol.Symbol, fmt.Sprintf(
-			`<img class="symbol" title="%v" alt="%v" src="%v">`,
-			cardSymbol.Symbol, cardSymbol.Symbol, cardSymbol.SVGURI,
-		))
+	dec := json.NewDecoder(fp)
+	if _, err := dec.Token(); err != nil {
+		return nil, err
 	}
 
-	return strings.NewReplacer(replace...), nil
+	var names []string
+	for dec.More() {
+		var card ScryfallCard
+		err := dec.Decode(&card)
+		if err != nil {
+			return nil, err
+		}
+
+		if nonCardLayouts[card.Layout] {
+			continue
+		}
+
+		if len(card.CardFaces) == 0 {
+			names = append(names, card.Name)
+		}
+		for _, face := range card.CardFaces {
+			names = append(names, face.Name)
+		}
+	}
+
+	return names, nil
+}
+
+var loadCardNames = sync.OnceValues(readCardNames)
+
+// detectCards records the cards mentioned in the rules when the Scryfall bulk
+// data is given with --cards.
+func detectCards(rules *parser.Rules) error {
+	if cardsFile == "" {
+		return nil
+	}
+
+	names, err := loadCardNames()
+	if err != nil {
+		return fmt.Errorf("reading cards: %w", err)
+	}
+
+	rules.DetectCards(names)
+	return nil
 }
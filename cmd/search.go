@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var searchLimit int
+
+// maxSnippetLength is the maximum length of the text shown for each search
+// result.
+const maxSnippetLength = 120
+
+func snippet(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+
+	runes := []rune(s)
+	if len(runes) <= maxSnippetLength {
+		return s
+	}
+
+	return string(runes[:maxSnippetLength-1]) + "…"
+}
+
+func searchRun(cmd *cobra.Command, args []string) error {
+	rules, err := openParsedRules()
+	if err != nil {
+		return err
+	}
+
+	f := newTerminalFormatter(rules)
+	out := cmd.OutOrStdout()
+
+	results := rules.Search(strings.Join(args, " "), searchLimit)
+	if len(results) == 0 {
+		return fmt.Errorf("no rules found matching %q", strings.Join(args, " "))
+	}
+
+	for _, result := range results {
+		if result.Section != nil {
+			fmt.Fprintf(out, "%s %s\n", f.style(result.Section.Number, ansiBold), f.Text(snippet(result.Section.Body[0])))
+			continue
+		}
+
+		item := result.GlossaryItem
+		fmt.Fprintf(out, "%s %s\n", f.style(item.KeyText, ansiBold, ansiCyan), f.Text(snippet(item.Body)))
+	}
+
+	return nil
+}
+
+var searchCmd = &cobra.Command{
+	Use:     "search <query>",
+	Short:   "Search the rules and glossary",
+	Example: `  rulesraker search "first strike"`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    searchRun,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 10,
+		"maximum number of results to show",
+	)
+	searchCmd.Flags().BoolVar(&noColor, "no-color", false,
+		"disable colored output",
+	)
+}
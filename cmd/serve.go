@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var serveAddr string
+
+const (
+	liveReloadPath       = "/_livereload"
+	liveReloadScriptPath = "/_livereload.js"
+)
+
+// liveReloadScript reloads the page when the server tells it that the site
+// was rebuilt. It is served from the same origin so that the content security
+// policy of the page doesn't need to be changed.
+const liveReloadScript = `"use strict";
+
+(function () {
+  var source = new EventSource("` + liveReloadPath + `");
+  source.addEventListener("reload", function () {
+    window.location.reload();
+  });
+})();
+`
+
+// liveReload keeps track of the pages connected to the live reload event
+// stream.
+type liveReload struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+func newLiveReload() *liveReload {
+	return &liveReload{clients: make(map[chan struct{}]struct{})}
+}
+
+// Notify tells all of the connected pages to reload.
+func (lr *liveReload) Notify() {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	for client := range lr.clients {
+		select {
+		case client <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (lr *liveReload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	client := make(chan struct{}, 1)
+
+	lr.mu.Lock()
+	lr.clients[client] = struct{}{}
+	lr.mu.Unlock()
+
+	defer func() {
+		lr.mu.Lock()
+		delete(lr.clients, client)
+		lr.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	err := rc.Flush()
+	if err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-client:
+			_, err := fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			if err != nil {
+				return
+			}
+
+			err = rc.Flush()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// injectLiveReload adds the live reload script to the end of the body of an
+// HTML page.
+func injectLiveReload(page []byte) []byte {
+	script := []byte(fmt.Sprintf(`<script src="%s" defer></script>`, liveReloadScriptPath))
+
+	i := bytes.LastIndex(page, []byte("</body>"))
+	if i < 0 {
+		return append(page, script...)
+	}
+
+	return append(page[:i:i], append(script, page[i:]...)...)
+}
+
+// siteHandler serves the built site from the output directory like it would
+// be served in production, including serving 404.html for unknown paths.
+type siteHandler struct {
+	root       string
+	notFound   string
+	liveReload bool
+}
+
+func (h siteHandler) serveFile(w http.ResponseWriter, r *http.Request, name string, status int) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+
+	if h.liveReload && strings.HasPrefix(contentType, "text/html") {
+		content = injectLiveReload(content)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+		_, _ = w.Write(content)
+		return
+	}
+
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(content))
+}
+
+func (h siteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	urlPath := path.Clean("/" + r.URL.Path)
+	if strings.HasSuffix(r.URL.Path, "/") {
+		urlPath = path.Join(urlPath, "index.html")
+	}
+
+	name := filepath.Join(h.root, filepath.FromSlash(urlPath))
+
+	info, err := os.Stat(name)
+	if err == nil && info.IsDir() {
+		http.Redirect(w, r, urlPath+"/", http.StatusMovedPermanently)
+		return
+	}
+
+	if errors.Is(err, fs.ErrNotExist) {
+		h.serveFile(w, r, h.notFound, http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	h.serveFile(w, r, name, http.StatusOK)
+}
+
+func serveRun(cmd *cobra.Command, args []string) error {
+	lr := newLiveReload()
+
+	mux := http.NewServeMux()
+	mux.Handle("/", siteHandler{
+		root:       outputDir,
+		notFound:   filepath.Join(publicDir, "404.html"),
+		liveReload: watch,
+	})
+
+	if watch {
+		mux.Handle(liveReloadPath, lr)
+		mux.HandleFunc(liveReloadScriptPath, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+			w.Header().Set("Cache-Control", "no-cache")
+			_, _ = fmt.Fprint(w, liveReloadScript)
+		})
+	}
+
+	srv := &http.Server{
+		Addr:              serveAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errServe := make(chan error, 1)
+	go func() {
+		cmd.Printf("serving %q on http://%s\n", outputDir, serveAddr)
+		errServe <- srv.ListenAndServe()
+	}()
+
+	// The build blocks when watching, so it runs alongside the server and
+	// whichever fails first stops the command.
+	errBuild := make(chan error, 1)
+	go func() {
+		errBuild <- buildSite(cmd, lr.Notify)
+	}()
+
+	select {
+	case err := <-errServe:
+		return err
+	case err := <-errBuild:
+		if err != nil {
+			return errors.Join(err, srv.Close())
+		}
+	}
+
+	return <-errServe
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Build the site and serve it locally",
+	Args:  cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return os.MkdirAll(outputDir, 0o755)
+	},
+	RunE: serveRun,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	addBuildFlags(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "localhost:8080",
+		"address to serve the site on",
+	)
+}
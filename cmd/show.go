@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+var showRecursive bool
+
+func printSectionTree(w io.Writer, f terminalFormatter, rules parser.Rules, section parser.Section, recursive bool) {
+	fmt.Fprint(w, f.Section(section))
+
+	children, _ := rules.Children(section.ID)
+	for _, child := range children {
+		if recursive {
+			printSectionTree(w, f, rules, child, recursive)
+		} else {
+			fmt.Fprint(w, f.Section(child))
+		}
+	}
+}
+
+func showRun(cmd *cobra.Command, args []string) error {
+	rules, err := openParsedRules()
+	if err != nil {
+		return err
+	}
+
+	f := newTerminalFormatter(rules)
+
+	for i, id := range args {
+		section, ok := rules.Section(id)
+		if !ok {
+			return fmt.Errorf("rule %q not found", id)
+		}
+
+		if i > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+
+		printSectionTree(cmd.OutOrStdout(), f, rules, section, showRecursive)
+	}
+
+	return nil
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show <rule>...",
+	Short: "Show a rule with its subrules and examples",
+	Example: `  rulesraker show 702.19b
+  rulesraker show --recursive 702`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: showRun,
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().BoolVarP(&showRecursive, "recursive", "r", false,
+		"show all of the rules below the rule instead of only the direct subrules",
+	)
+	showCmd.Flags().BoolVar(&noColor, "no-color", false,
+		"disable colored output",
+	)
+}
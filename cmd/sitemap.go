@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// sitemapEntry is a single page of the built site which should be listed in
+// the sitemap.
+type sitemapEntry struct {
+	URL          string
+	LastModified time.Time
+}
+
+// SitemapEntries returns the pages rendered from the data.
+func (d indexData) SitemapEntries() []sitemapEntry {
+	return []sitemapEntry{{d.URL, d.EffectiveDate}}
+}
+
+// StructuredData returns the schema.org description of the page, it is
+// embedded into the page as JSON-LD.
+func (d indexData) StructuredData() map[string]any {
+	effectiveDate := d.EffectiveDate.Format("2006-01-02")
+
+	return map[string]any{
+		"@context":     "https://schema.org",
+		"@type":        "WebPage",
+		"url":          d.URL,
+		"name":         d.Title,
+		"description":  d.Description,
+		"dateModified": effectiveDate,
+		"mainEntity": map[string]any{
+			"@type":         "DigitalDocument",
+			"name":          "Magic: The Gathering Comprehensive Rules",
+			"version":       effectiveDate,
+			"datePublished": effectiveDate,
+			"inLanguage":    "en",
+			"url":           d.RulesURL,
+			"publisher": map[string]any{
+				"@type": "Organization",
+				"name":  "Wizards of the Coast",
+			},
+		},
+	}
+}
+
+func renderSitemap(w io.Writer, entries []sitemapEntry) error {
+	urlSet := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for _, entry := range entries {
+		url := sitemapURL{Loc: entry.URL}
+		if !entry.LastModified.IsZero() {
+			url.LastMod = entry.LastModified.Format("2006-01-02")
+		}
+
+		urlSet.URLs = append(urlSet.URLs, url)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	err = enc.Encode(urlSet)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func renderRobots(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "User-agent: *\nDisallow:\n\nSitemap: %s/sitemap.xml\n", siteURL)
+	return err
+}
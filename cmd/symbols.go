@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var symbolsAlways bool
+
+// writeFileAtomic writes data to a temporary file in the same directory as
+// name and renames it over name once fully written.
+func writeFileAtomic(name string, r io.Reader) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = io.Copy(tmpFile, r)
+	if err != nil {
+		return errors.Join(err, tmpFile.Close())
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), name)
+}
+
+func symbolsSyncRun(cmd *cobra.Command, args []string) error {
+	cmd.Printf("getting symbology from %q\n", scryfallSymbologyURL)
+	resp, err := scryfallGet(scryfallSymbologyURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var symbols CardSymbolList
+	err = json.Unmarshal(body, &symbols)
+	if err != nil {
+		return err
+	}
+
+	var errDownload error
+	for _, cardSymbol := range symbols.Data {
+		fileName, err := cardSymbol.FileName()
+		if err != nil {
+			errDownload = errors.Join(errDownload, err)
+			continue
+		}
+
+		path := filepath.Join(symbolsDir(), fileName)
+		if _, err := os.Stat(path); err == nil && !symbolsAlways {
+			continue
+		}
+
+		cmd.Printf("downloading %s from %q\n", cardSymbol.Symbol, cardSymbol.SVGURI)
+		resp, err := scryfallGet(cardSymbol.SVGURI)
+		if err != nil {
+			errDownload = errors.Join(errDownload, err)
+			continue
+		}
+
+		errDownload = errors.Join(errDownload, writeFileAtomic(path, resp.Body), resp.Body.Close())
+	}
+
+	if errDownload != nil {
+		return errDownload
+	}
+
+	// The symbology is written last so that it never references symbols which
+	// were not downloaded.
+	return writeFileAtomic(symbologyPath(), bytes.NewReader(body))
+}
+
+var symbolsCmd = &cobra.Command{
+	Use:   "symbols",
+	Short: "Manage the local cache of Scryfall card symbols",
+	Args:  cobra.NoArgs,
+}
+
+var symbolsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Download the card symbology and symbol images from Scryfall",
+	Args:  cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return os.MkdirAll(symbolsDir(), 0o755)
+	},
+	RunE: symbolsSyncRun,
+}
+
+func init() {
+	rootCmd.AddCommand(symbolsCmd)
+	symbolsCmd.AddCommand(symbolsSyncCmd)
+
+	symbolsSyncCmd.Flags().BoolVar(&symbolsAlways, "always", false,
+		"always download the symbol images, even if they already exist",
+	)
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xremming/rulesraker/parser"
+)
+
+var noColor bool
+
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiDim       = "\x1b[2m"
+	ansiItalic    = "\x1b[3m"
+	ansiUnderline = "\x1b[4m"
+	ansiRed       = "\x1b[31m"
+	ansiGreen     = "\x1b[32m"
+	ansiYellow    = "\x1b[33m"
+	ansiBlue      = "\x1b[34m"
+	ansiMagenta   = "\x1b[35m"
+	ansiCyan      = "\x1b[36m"
+)
+
+// openParsedRules reads the rules written by the parse command.
+func openParsedRules() (parser.Rules, error) {
+	fp, err := os.Open(filepath.Join(dataDir, "MagicCompRules.json"))
+	if err != nil {
+		return parser.Rules{}, err
+	}
+	defer fp.Close()
+
+	var rules parser.Rules
+	err = json.NewDecoder(fp).Decode(&rules)
+	if err != nil {
+		return parser.Rules{}, err
+	}
+
+	return rules, nil
+}
+
+// useColor reports whether output to the file should be formatted with ANSI
+// escape codes.
+func useColor(fp *os.File) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	info, err := fp.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalFormatter formats rules text for the terminal.
+type terminalFormatter struct {
+	color     bool
+	tokenizer *parser.Tokenizer
+}
+
+func newTerminalFormatter(rules parser.Rules) terminalFormatter {
+	return terminalFormatter{useColor(os.Stdout), parser.NewTokenizer(rules)}
+}
+
+func (f terminalFormatter) style(s string, codes ...string) string {
+	if !f.color || len(codes) == 0 {
+		return s
+	}
+
+	return strings.Join(codes, "") + s + ansiReset
+}
+
+// symbolColor returns the color of a symbol based on the first color of mana
+// it contains.
+func symbolColor(symbol string) string {
+	inner := strings.Trim(symbol, "{}")
+
+	switch {
+	case strings.Contains(inner, "W"):
+		return ansiYellow
+	case strings.Contains(inner, "U"):
+		return ansiBlue
+	case strings.Contains(inner, "B"):
+		return ansiMagenta
+	case strings.Contains(inner, "R"):
+		return ansiRed
+	case strings.Contains(inner, "G"):
+		return ansiGreen
+	}
+
+	return ansiCyan
+}
+
+// Text formats rules text, mana and other symbols such as {W} and {T} are
+// kept as text but highlighted, references are underlined and card names are
+// bold.
+func (f terminalFormatter) Text(s string) string {
+	if !f.color {
+		return s
+	}
+
+	var out strings.Builder
+	for _, inline := range f.tokenizer.Tokenize(s) {
+		switch inline.Kind {
+		case parser.InlineSymbol:
+			out.WriteString(f.style(inline.Text, ansiBold, symbolColor(inline.Text)))
+		case parser.InlineRuleRef, parser.InlineGlossaryTerm, parser.InlineLink, parser.InlineDocumentRef:
+			out.WriteString(f.style(inline.Text, ansiUnderline))
+		case parser.InlineCard:
+			out.WriteString(f.style(inline.Text, ansiBold))
+		default:
+			out.WriteString(inline.Text)
+		}
+	}
+
+	return out.String()
+}
+
+func (f terminalFormatter) Section(section parser.Section) string {
+	var out strings.Builder
+
+	number := f.style(section.Number, ansiBold)
+	switch section.Type {
+	case parser.Part, parser.Chapter:
+		out.WriteString(number + " " + f.style(section.Body[0], ansiBold) + "\n")
+	default:
+		for i, line := range section.Body {
+			if i == 0 {
+				out.WriteString(number + " " + f.Text(line) + "\n")
+			} else {
+				out.WriteString(f.Text(line) + "\n")
+			}
+		}
+	}
+
+	for _, example := range section.Examples {
+		out.WriteString(f.style("Example:", ansiItalic, ansiDim) + " " + f.style(f.Text(example), ansiItalic) + "\n")
+	}
+
+	return out.String()
+}
+
+func (f terminalFormatter) GlossaryItem(item parser.GlossaryItem) string {
+	return f.style(item.KeyText, ansiBold) + "\n" + f.Text(item.Body) + "\n"
+}
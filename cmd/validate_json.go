@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/xremming/rulesraker/parser"
+)
+
+func validateJSONRun(cmd *cobra.Command, args []string) error {
+	path := filepath.Join(dataDir, "MagicCompRules.json")
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	err = parser.ValidateJSON(data)
+	if err != nil {
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			for _, err := range joined.Unwrap() {
+				cmd.PrintErrln(err)
+			}
+
+			return fmt.Errorf("%s does not match format version %d, found %d errors", path, parser.FormatVersion, len(joined.Unwrap()))
+		}
+
+		return err
+	}
+
+	cmd.Printf("%s matches format version %d\n", path, parser.FormatVersion)
+	return nil
+}
+
+var validateJSONCmd = &cobra.Command{
+	Use:   "validate-json [file]",
+	Short: "Validate a .json rules file against the JSON Schema of the current format",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  validateJSONRun,
+}
+
+func init() {
+	rootCmd.AddCommand(validateJSONCmd)
+}
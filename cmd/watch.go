@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// watchDebounce is how long to wait for more file system events before the
+// changes are handled. Editors and git tend to produce bursts of events for a
+// single save.
+const watchDebounce = 100 * time.Millisecond
+
+// watchChanges watches the directories for changes and calls onChange with the
+// cleaned names of the changed files once the events have settled down. It
+// returns only when the watcher fails.
+func watchChanges(cmd *cobra.Command, dirs []string, onChange func(changed []string)) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	for _, dir := range dirs {
+		err = w.Add(dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	cmd.Println("watching for file system changes")
+
+	changed := make(map[string]struct{})
+	var debounce <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			changed[filepath.Clean(event.Name)] = struct{}{}
+			debounce = time.After(watchDebounce)
+		case <-debounce:
+			names := make([]string, 0, len(changed))
+			for name := range changed {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+
+			clear(changed)
+			debounce = nil
+
+			onChange(names)
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+// relativeTo returns the path of name relative to dir if name is inside dir.
+func relativeTo(dir, name string) (string, bool) {
+	rel, err := filepath.Rel(filepath.Clean(dir), name)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel), true
+}
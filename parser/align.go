@@ -0,0 +1,51 @@
+package parser
+
+// AlignedSection is a section of the English rules paired with the same
+// section of a translation. Translated is nil for sections missing from the
+// translation and English is nil for sections only in the translation.
+type AlignedSection struct {
+	ID         string
+	English    *Section `json:",omitempty"`
+	Translated *Section `json:",omitempty"`
+}
+
+// Align pairs the sections of a translation with the sections of the English
+// rules by their number. The sections are returned in the order of the English
+// rules followed by the sections only in the translation in their order.
+func Align(english, translated Rules) []AlignedSection {
+	translatedByID := make(map[string]*Section, len(translated.Rules))
+	for i := range translated.Rules {
+		translatedByID[translated.Rules[i].ID] = &translated.Rules[i]
+	}
+
+	englishByID := make(map[string]bool, len(english.Rules))
+
+	out := make([]AlignedSection, 0, len(english.Rules))
+	for i := range english.Rules {
+		section := &english.Rules[i]
+		englishByID[section.ID] = true
+		out = append(out, AlignedSection{section.ID, section, translatedByID[section.ID]})
+	}
+
+	for i := range translated.Rules {
+		section := &translated.Rules[i]
+		if !englishByID[section.ID] {
+			out = append(out, AlignedSection{section.ID, nil, section})
+		}
+	}
+
+	return out
+}
+
+// MissingTranslations returns the IDs of the sections of the English rules
+// which are missing from the translation.
+func MissingTranslations(english, translated Rules) []string {
+	var out []string
+	for _, section := range Align(english, translated) {
+		if section.Translated == nil {
+			out = append(out, section.ID)
+		}
+	}
+
+	return out
+}
@@ -51,9 +51,13 @@ func TestParseArchive(t *testing.T) {
 			}
 			defer f.Close()
 
-			rules, err := Parse(f)
+			rules, diagnostics, err := Parse(f)
 			if err != nil {
-				t.Errorf("Failed to parse: %v", err)
+				for _, d := range diagnostics {
+					if d.Severity == SeverityError {
+						t.Errorf("%s:%v", path, d)
+					}
+				}
 			}
 
 			if rules.EffectiveDate.IsZero() {
@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CardMatcher finds the names of cards in rules text. Names are matched case
+// sensitively as whole words, the longest name wins when names overlap.
+type CardMatcher struct {
+	// byFirstWord has the names starting with the word, longest first.
+	byFirstWord map[string][]string
+}
+
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '’' || r == '-'
+}
+
+// firstWord returns the word at the start of s.
+func firstWord(s string) string {
+	for i, r := range s {
+		if !isNameRune(r) {
+			return s[:i]
+		}
+	}
+
+	return s
+}
+
+// normalizeCardName returns the name as written in the rules, which use
+// typographic apostrophes.
+func normalizeCardName(name string) string {
+	return strings.ReplaceAll(strings.TrimSpace(name), "'", "’")
+}
+
+func NewCardMatcher(names []string) *CardMatcher {
+	m := &CardMatcher{byFirstWord: make(map[string][]string)}
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = normalizeCardName(name)
+		word := firstWord(name)
+		if word == "" || seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		m.byFirstWord[word] = append(m.byFirstWord[word], name)
+	}
+
+	for _, names := range m.byFirstWord {
+		slices.SortFunc(names, func(a, b string) int {
+			return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b))
+		})
+	}
+
+	return m
+}
+
+// cardSpan is a card name found in text at the byte offsets.
+type cardSpan struct {
+	start int
+	end   int
+	name  string
+}
+
+// find returns the card names in the text in the order they appear.
+func (m *CardMatcher) find(s string) []cardSpan {
+	var out []cardSpan
+
+	prev := rune(0)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if isNameRune(prev) || !isNameRune(r) {
+			prev = r
+			i += size
+			continue
+		}
+
+		matched := false
+		for _, name := range m.byFirstWord[firstWord(s[i:])] {
+			if !strings.HasPrefix(s[i:], name) {
+				continue
+			}
+
+			next, _ := utf8.DecodeRuneInString(s[i+len(name):])
+			if i+len(name) < len(s) && isNameRune(next) {
+				continue
+			}
+
+			out = append(out, cardSpan{i, i + len(name), name})
+			prev, _ = utf8.DecodeLastRuneInString(name)
+			i += len(name)
+			matched = true
+			break
+		}
+
+		if !matched {
+			prev = r
+			i += size
+		}
+	}
+
+	return out
+}
+
+// Names returns the card names in the text in the order they are first
+// mentioned.
+func (m *CardMatcher) Names(s string) []string {
+	var out []string
+	for _, span := range m.find(s) {
+		if !slices.Contains(out, span.name) {
+			out = append(out, span.name)
+		}
+	}
+
+	return out
+}
+
+// rulesTerms returns the lowercase words and terms of the rules which are not
+// counted as card names: the glossary terms, the keywords and the words of
+// rule 205, which lists the card types and subtypes.
+func rulesTerms(r Rules) map[string]bool {
+	terms := make(map[string]bool)
+
+	for _, item := range r.Glossary {
+		for _, part := range item.KeyParts {
+			terms[strings.ToLower(part)] = true
+		}
+	}
+
+	for _, keyword := range r.Keywords {
+		terms[strings.ToLower(keyword.Name)] = true
+	}
+
+	for _, section := range r.Rules {
+		if !strings.HasPrefix(section.ID, "205.") {
+			continue
+		}
+
+		for _, line := range section.Body {
+			for _, word := range strings.FieldsFunc(line, func(r rune) bool { return !isNameRune(r) }) {
+				terms[strings.ToLower(word)] = true
+			}
+		}
+	}
+
+	return terms
+}
+
+// DetectCards records the names of the cards mentioned in the body and the
+// examples of each section. Names which are also terms of the rules, such as
+// "Forest" or "Flying", are left out as they are mostly used as terms.
+func (r *Rules) DetectCards(names []string) {
+	terms := rulesTerms(*r)
+
+	var cardNames []string
+	for _, name := range names {
+		if utf8.RuneCountInString(name) >= 3 && !terms[strings.ToLower(normalizeCardName(name))] {
+			cardNames = append(cardNames, name)
+		}
+	}
+
+	m := NewCardMatcher(cardNames)
+	for i := range r.Rules {
+		section := &r.Rules[i]
+
+		section.Cards = []string{}
+		for _, text := range slices.Concat(section.Body, section.Examples) {
+			for _, name := range m.Names(text) {
+				if !slices.Contains(section.Cards, name) {
+					section.Cards = append(section.Cards, name)
+				}
+			}
+		}
+	}
+}
+
+// SectionsMentioningCard returns the sections which mention the card, the
+// name is matched case insensitively.
+func (r Rules) SectionsMentioningCard(name string) []Section {
+	name = normalizeCardName(name)
+
+	var out []Section
+	for _, section := range r.Rules {
+		if slices.ContainsFunc(section.Cards, func(card string) bool {
+			return strings.EqualFold(card, name)
+		}) {
+			out = append(out, section)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCardMatcher(t *testing.T) {
+	m := NewCardMatcher([]string{"Llanowar Elves", "Llanowar", "Giant Growth", "Ajani's Pridemate", "Elves"})
+
+	tests := map[string][]string{
+		"Llanowar Elves taps for G.":                {"Llanowar Elves"},
+		"Cast Giant Growth targeting Llanowar.":     {"Giant Growth", "Llanowar"},
+		"Ajani’s Pridemate gets a counter.":         {"Ajani’s Pridemate"},
+		"Giant Growths and Elvesong are not cards.": nil,
+		"Elves, Elves and Llanowar Elves.":          {"Elves", "Llanowar Elves"},
+	}
+
+	for text, expected := range tests {
+		if names := m.Names(text); !slices.Equal(names, expected) {
+			t.Errorf("Names(%q) = %q, expected %q", text, names, expected)
+		}
+	}
+}
+
+func TestDetectCards(t *testing.T) {
+	rules := Rules{
+		Rules: []Section{
+			{ID: "205.3m", Body: []string{"Creatures have their own subtypes: Elf and Goblin."}},
+			{ID: "702.9a", Body: []string{"Flying is an evasion ability."}, Examples: []string{"Example: Goblin attacks and Birds of Paradise blocks."}},
+		},
+		Keywords: []Keyword{{Name: "Flying"}},
+	}
+
+	rules.DetectCards([]string{"Flying", "Goblin", "Birds of Paradise", "Ox"})
+
+	section, _ := rules.Section("702.9a")
+	if expected := []string{"Birds of Paradise"}; !slices.Equal(section.Cards, expected) {
+		t.Errorf("Cards = %q, expected %q", section.Cards, expected)
+	}
+
+	if sections := rules.SectionsMentioningCard("birds of paradise"); len(sections) != 1 || sections[0].ID != "702.9a" {
+		t.Errorf("SectionsMentioningCard returned %v", sections)
+	}
+}
@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrRuleNotFound is returned when a cited rule is not in the rules.
+var ErrRuleNotFound = errors.New("rule not found")
+
+// Citation is a rule or a range of rules quoted from a version of the rules
+// together with all of the subrules below them.
+type Citation struct {
+	Ref           string    `json:"ref"`
+	EffectiveDate time.Time `json:"effectiveDate"`
+	Sections      []Section `json:"sections"`
+}
+
+// citationRef returns the reference of the numbers as written in the rules,
+// e.g. "702.19b", "601.2a–f" or "702.19–21".
+func citationRef(numbers []RuleNumber) string {
+	start := strings.TrimSuffix(numbers[0].String(), ".")
+	if len(numbers) == 1 {
+		return start
+	}
+
+	end := numbers[len(numbers)-1]
+	switch end.Type() {
+	case SubRule:
+		return start + "–" + end.Letter
+	case Rule:
+		return fmt.Sprintf("%s–%d", start, end.Rule)
+	default:
+		return start + "–" + strings.TrimSuffix(end.String(), ".")
+	}
+}
+
+// Cite returns the citation of a rule or a range of rules, e.g. "702.19b" or
+// "601.2a–f". Every cited section is followed by the sections below it. The
+// error wraps ErrRuleNotFound when the reference is valid but a cited rule is
+// missing.
+func (r Rules) Cite(ref string) (Citation, error) {
+	numbers, err := ExpandRuleRange(ref)
+	if err != nil {
+		return Citation{}, err
+	}
+
+	c := Citation{
+		Ref:           citationRef(numbers),
+		EffectiveDate: r.EffectiveDate,
+	}
+
+	for _, n := range numbers {
+		i := r.index(n.String())
+		if i < 0 {
+			return Citation{}, fmt.Errorf("%w: %s", ErrRuleNotFound, strings.TrimSuffix(n.String(), "."))
+		}
+
+		c.Sections = append(c.Sections, r.Rules[i])
+		for _, section := range r.Rules[i+1:] {
+			below, err := ParseRuleNumber(section.ID)
+			if err != nil || !n.IsAncestorOf(below) {
+				break
+			}
+
+			c.Sections = append(c.Sections, section)
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCite(t *testing.T) {
+	rules := Rules{
+		Rules: []Section{
+			{ID: "601.", Type: Chapter},
+			{ID: "601.1.", Type: Rule},
+			{ID: "601.2.", Type: Rule},
+			{ID: "601.2a", Type: SubRule},
+			{ID: "601.2b", Type: SubRule},
+			{ID: "601.2c", Type: SubRule},
+			{ID: "601.3.", Type: Rule},
+		},
+	}
+
+	tests := map[string]struct {
+		ref string
+		ids []string
+	}{
+		"601.2b":       {"601.2b", []string{"601.2b"}},
+		"601.2":        {"601.2", []string{"601.2.", "601.2a", "601.2b", "601.2c"}},
+		"601.2a-c":     {"601.2a–c", []string{"601.2a", "601.2b", "601.2c"}},
+		"601.2–601.3.": {"601.2–3", []string{"601.2.", "601.2a", "601.2b", "601.2c", "601.3."}},
+		"601":          {"601", []string{"601.", "601.1.", "601.2.", "601.2a", "601.2b", "601.2c", "601.3."}},
+	}
+
+	for input, expected := range tests {
+		c, err := rules.Cite(input)
+		if err != nil {
+			t.Errorf("Cite(%q) returned %v", input, err)
+			continue
+		}
+
+		var ids []string
+		for _, section := range c.Sections {
+			ids = append(ids, section.ID)
+		}
+
+		if c.Ref != expected.ref || !slices.Equal(ids, expected.ids) {
+			t.Errorf("Cite(%q) = %q %v, expected %q %v", input, c.Ref, ids, expected.ref, expected.ids)
+		}
+	}
+
+	// Missing rules are told apart from invalid references.
+	for input, notFound := range map[string]bool{"601.2d": true, "601.2a-e": true, "nonsense": false} {
+		if _, err := rules.Cite(input); err == nil || errors.Is(err, ErrRuleNotFound) != notFound {
+			t.Errorf("Cite(%q) returned %v, expected an error with not found %v", input, err, notFound)
+		}
+	}
+}
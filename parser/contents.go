@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"strings"
+)
+
+// ContentsEntry is a line of the table of contents.
+type ContentsEntry struct {
+	Number string `json:"number,omitempty" doc:"Number of the part or chapter, empty for the glossary and credits."`
+	Title  string `json:"title" doc:"Title of the entry as written in the table of contents."`
+}
+
+func parseIntroduction(blocks []block) []string {
+	out := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		out = append(out, b.text)
+	}
+
+	return out
+}
+
+func parseContents(blocks []block) ([]ContentsEntry, []position) {
+	var (
+		out       []ContentsEntry
+		positions []position
+	)
+	for _, b := range blocks {
+		for i, line := range strings.Split(b.text, "\n") {
+			entry := ContentsEntry{Title: line}
+
+			title, rawNumber, ok := parseNumber(line)
+			if number, err := ParseRuleNumber(rawNumber); ok && err == nil {
+				entry = ContentsEntry{number.String(), strings.TrimSpace(title)}
+			}
+
+			out = append(out, entry)
+			positions = append(positions, b.lines[i])
+		}
+	}
+
+	return out, positions
+}
+
+// checkContents checks that every part and chapter in the table of contents
+// was parsed with the same title and that every parsed part and chapter is in
+// the table of contents. Missing entries are reported at the start of the table
+// of contents.
+func checkContents(contents []ContentsEntry, positions []position, rules []Section) diagnostics {
+	titles := make(map[string]string)
+	for _, section := range rules {
+		if section.Type == Part || section.Type == Chapter {
+			titles[section.ID] = section.Body[0]
+		}
+	}
+
+	var ds diagnostics
+	listed := make(map[string]bool)
+	for i, entry := range contents {
+		if entry.Number == "" {
+			continue
+		}
+		listed[entry.Number] = true
+
+		title, ok := titles[entry.Number]
+		if !ok {
+			ds.add(SeverityWarning, CodeContentsMismatch, positions[i], entry.Number, "table of contents entry %q was not found in the rules", entry.Title)
+		} else if title != entry.Title {
+			ds.add(SeverityWarning, CodeContentsMismatch, positions[i], entry.Number, "table of contents entry %q does not match the title %q in the rules", entry.Title, title)
+		}
+	}
+
+	// Without any numbered entries there is no table of contents to check.
+	if len(listed) == 0 {
+		return ds
+	}
+
+	for _, section := range rules {
+		if (section.Type == Part || section.Type == Chapter) && !listed[section.ID] {
+			ds.add(SeverityWarning, CodeContentsMismatch, positions[0], section.ID, "%s %q is missing from the table of contents", section.Number, section.Body[0])
+		}
+	}
+
+	return ds
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseContents(t *testing.T) {
+	rules, ds, err := Parse(strings.NewReader(testRules))
+	if err != nil || len(ds) != 0 {
+		t.Fatalf("Parse returned %v, %v", ds, err)
+	}
+
+	if expected := []string{"This document is the ultimate authority for Magic competitive game play."}; !slices.Equal(rules.Introduction, expected) {
+		t.Errorf("Introduction = %q, expected %q", rules.Introduction, expected)
+	}
+
+	expected := []ContentsEntry{{"1.", "Game Concepts"}, {"100.", "General"}, {"", "Glossary"}, {"", "Credits"}}
+	if !slices.Equal(rules.TableOfContents, expected) {
+		t.Errorf("TableOfContents = %v, expected %v", rules.TableOfContents, expected)
+	}
+}
+
+func TestCheckContents(t *testing.T) {
+	tests := map[string]struct {
+		old, new string
+		expected []Diagnostic
+	}{
+		"extra entry": {
+			"100. General\n\nGlossary", "100. General\n101. Other\n\nGlossary",
+			[]Diagnostic{{Severity: SeverityWarning, Code: CodeContentsMismatch, Line: 13, Column: 1, RuleID: "101."}},
+		},
+		"missing entry": {
+			"1. Game Concepts\n100. General\n\nGlossary", "1. Game Concepts\n\nGlossary",
+			[]Diagnostic{{Severity: SeverityWarning, Code: CodeContentsMismatch, Line: 11, Column: 1, RuleID: "100."}},
+		},
+		"different title": {
+			"1. Game Concepts\n100.", "1. Game Rules\n100.",
+			[]Diagnostic{{Severity: SeverityWarning, Code: CodeContentsMismatch, Line: 11, Column: 1, RuleID: "1."}},
+		},
+	}
+
+	for name, test := range tests {
+		input := strings.Replace(testRules, test.old, test.new, 1)
+		if input == testRules {
+			t.Fatalf("%s: %q not found in the test rules", name, test.old)
+		}
+
+		_, ds, err := Parse(strings.NewReader(input))
+		if err != nil {
+			t.Errorf("%s: Parse returned %v", name, err)
+		}
+
+		for i := range ds {
+			ds[i].Message = ""
+		}
+
+		if !slices.Equal(ds, test.expected) {
+			t.Errorf("%s: Parse returned diagnostics %v, expected %v", name, ds, test.expected)
+		}
+	}
+}
@@ -1,11 +1,144 @@
 package parser
 
-import "fmt"
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
 
-func parseCredits(credits []string) ([]string, error) {
-	if len(credits) == 0 {
-		return nil, fmt.Errorf("zero paragraphs of credits found")
+// Credits is the credits section at the end of the rules.
+type Credits struct {
+	Paragraphs      []string      `json:"paragraphs" doc:"Paragraphs of the credits section."`
+	Roles           []CreditsRole `json:"roles" doc:"Roles and the people credited for them in document order."`
+	Thanks          []string      `json:"thanks" doc:"Paragraphs thanking people without naming them."`
+	Publisher       string        `json:"publisher,omitempty" doc:"Name and address of the publisher."`
+	Copyright       string        `json:"copyright,omitempty" doc:"Copyright line of the publisher, e.g. \"©2025 Wizards\"."`
+	CopyrightYear   int           `json:"copyrightYear,omitempty" doc:"Year of the copyright line of the publisher."`
+	PublicationDate *time.Time    `json:"publicationDate,omitempty" doc:"Date from which the rules are in effect as stated in the credits."`
+}
+
+// CreditsRole is a role in the credits, e.g. "Editing", and the people
+// credited for it. Notes such as "(principal)" are left out of the names.
+type CreditsRole struct {
+	Role         string   `json:"role" doc:"Name of the role."`
+	Names        []string `json:"names" doc:"Names of the people credited for the role."`
+	Contributors []string `json:"contributors" doc:"Names of the people credited with contributions to the role."`
+}
+
+var (
+	designedByRegexp  = regexp.MustCompile(`^The (.+?) (?:was|were) designed by (.+)$`)
+	publishedByRegexp = regexp.MustCompile(`^Published by (.+?, (?:USA|U\.S\.A\.))`)
+	// Some versions have the copyright sign as an invalid byte, which is
+	// matched as the replacement character.
+	copyrightRegexp   = regexp.MustCompile(`(?:©|\((?i:c)\)|\x{FFFD}) ?(\d{4}) ([^.]+)`)
+	creditsDateRegexp = regexp.MustCompile(`^These rules are effective as of (\pL+) ?(\d+), (\d+)\.$`)
+	creditsNoteRegexp = regexp.MustCompile(` \([^()]*\)$`)
+)
+
+const contributionsSeparator = ", with contributions from "
+
+// splitNames splits a list of names such as "A, B, and C" into the names.
+func splitNames(s string) []string {
+	out := []string{}
+	for _, name := range strings.Split(s, ", ") {
+		name = strings.TrimPrefix(name, "and ")
+		for _, name := range strings.Split(name, " and ") {
+			name = creditsNoteRegexp.ReplaceAllString(strings.TrimSpace(name), "")
+			if name != "" {
+				out = append(out, name)
+			}
+		}
+	}
+
+	return out
+}
+
+func parseCreditsRole(role, names string) CreditsRole {
+	names, contributors, _ := strings.Cut(names, contributionsSeparator)
+	return CreditsRole{role, splitNames(names), splitNames(contributors)}
+}
+
+// parseDesignedBy parses sentences such as "The mana symbols were designed by
+// Christopher Rush." into roles named after what was designed.
+func parseDesignedBy(paragraph string) []CreditsRole {
+	var out []CreditsRole
+	for _, sentence := range strings.SplitAfter(paragraph, ". ") {
+		matches := designedByRegexp.FindStringSubmatch(strings.TrimSuffix(strings.TrimSpace(sentence), "."))
+		if matches == nil {
+			continue
+		}
+
+		first, size := utf8.DecodeRuneInString(matches[1])
+		role := string(unicode.ToUpper(first)) + matches[1][size:] + " design"
+		out = append(out, parseCreditsRole(role, matches[2]))
+	}
+
+	return out
+}
+
+func parseCredits(credits []block) Credits {
+	out := Credits{
+		Paragraphs: make([]string, 0, len(credits)),
+		Roles:      []CreditsRole{},
+		Thanks:     []string{},
+	}
+
+	// The roles are listed first, the rest are notices which could otherwise
+	// be mistaken for roles.
+	inRoles := true
+	for _, credit := range credits {
+		text := credit.text
+		out.Paragraphs = append(out.Paragraphs, text)
+
+		if matches := creditsDateRegexp.FindStringSubmatch(text); matches != nil {
+			// The date is left out when it is not a valid date, e.g. when
+			// the month is misspelled.
+			date, err := time.Parse("January 2, 2006", matches[1]+" "+matches[2]+", "+matches[3])
+			if err == nil {
+				out.PublicationDate = &date
+			}
+
+			inRoles = false
+			continue
+		}
+
+		if strings.HasPrefix(text, "Thanks to ") {
+			out.Thanks = append(out.Thanks, text)
+			inRoles = false
+			continue
+		}
+
+		if matches := publishedByRegexp.FindStringSubmatch(text); matches != nil {
+			inRoles = false
+			out.Publisher = matches[1]
+			if matches := copyrightRegexp.FindStringSubmatch(text); matches != nil {
+				out.Copyright = "©" + matches[1] + " " + matches[2]
+				out.CopyrightYear, _ = strconv.Atoi(matches[1])
+			}
+			continue
+		}
+
+		if !inRoles {
+			continue
+		}
+
+		if roles := parseDesignedBy(text); len(roles) > 0 {
+			out.Roles = append(out.Roles, roles...)
+			continue
+		}
+
+		for _, line := range strings.Split(text, "\n") {
+			i := strings.LastIndex(line, ": ")
+			if i < 0 {
+				continue
+			}
+
+			out.Roles = append(out.Roles, parseCreditsRole(line[:i], line[i+len(": "):]))
+		}
 	}
 
-	return credits, nil
+	return out
 }
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseCredits(t *testing.T) {
+	credits := parseCredits([]block{
+		{text: "Editing: Del Laugel (principal), Nat Moes, and Hans Ziegler\nRules Management: Jess Dunks and Eric Levine, with contributions from Mark Rosewater"},
+		{text: "The mana symbols were designed by Christopher Rush."},
+		{text: "Thanks to all our project team members."},
+		{text: "These rules are effective as of June18, 2021."},
+		{text: "Published by Wizards of the Coast LLC, PO Box 707, Renton, WA 98057-0707, USA. Magic is a trademark. (c)2021 Wizards. U.S. Pat. No. RE 37,957."},
+		{text: "Avatar: The Last Airbender is a trademark of Viacom International Inc."},
+	})
+
+	expected := []CreditsRole{
+		{"Editing", []string{"Del Laugel", "Nat Moes", "Hans Ziegler"}, []string{}},
+		{"Rules Management", []string{"Jess Dunks", "Eric Levine"}, []string{"Mark Rosewater"}},
+		{"Mana symbols design", []string{"Christopher Rush"}, []string{}},
+	}
+	if !slices.EqualFunc(credits.Roles, expected, func(a, b CreditsRole) bool {
+		return a.Role == b.Role && slices.Equal(a.Names, b.Names) && slices.Equal(a.Contributors, b.Contributors)
+	}) {
+		t.Errorf("Roles = %v, expected %v", credits.Roles, expected)
+	}
+
+	if len(credits.Thanks) != 1 || len(credits.Paragraphs) != 6 {
+		t.Errorf("Expected 1 thanks and 6 paragraphs, got %d and %d", len(credits.Thanks), len(credits.Paragraphs))
+	}
+
+	if credits.Publisher != "Wizards of the Coast LLC, PO Box 707, Renton, WA 98057-0707, USA" {
+		t.Errorf("Publisher = %q", credits.Publisher)
+	}
+
+	if credits.Copyright != "©2021 Wizards" || credits.CopyrightYear != 2021 {
+		t.Errorf("Copyright = %q %d", credits.Copyright, credits.CopyrightYear)
+	}
+
+	if credits.PublicationDate == nil || !credits.PublicationDate.Equal(time.Date(2021, time.June, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("PublicationDate = %s", credits.PublicationDate)
+	}
+}
+
+func TestParseCreditsInvalidDate(t *testing.T) {
+	credits := parseCredits([]block{{text: "These rules are effective as of Junr 18, 2021."}})
+	if credits.PublicationDate != nil {
+		t.Errorf("PublicationDate = %s, expected nil", credits.PublicationDate)
+	}
+}
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// DiagnosticCode identifies the kind of problem found while parsing.
+type DiagnosticCode string
+
+const (
+	CodeNoEffectiveDate    DiagnosticCode = "no-effective-date"
+	CodeNoRules            DiagnosticCode = "no-rules"
+	CodeNoGlossary         DiagnosticCode = "no-glossary"
+	CodeNoCredits          DiagnosticCode = "no-credits"
+	CodeInvalidNumber      DiagnosticCode = "invalid-number"
+	CodeContinuation       DiagnosticCode = "continuation"
+	CodeBodyAfterExamples  DiagnosticCode = "body-after-examples"
+	CodeEmptyBody          DiagnosticCode = "empty-body"
+	CodeInvalidHeading     DiagnosticCode = "invalid-heading"
+	CodeGlossaryItemNoBody DiagnosticCode = "glossary-item-no-body"
+	CodeContentsMismatch   DiagnosticCode = "contents-mismatch"
+	CodeUnknownRedirect    DiagnosticCode = "unknown-redirect"
+)
+
+// Diagnostic is a problem found while parsing. Line and Column are 1-based
+// positions in the source file, both are 0 when the problem is not about a
+// specific position. RuleID is set when the problem is within a rule.
+type Diagnostic struct {
+	Severity Severity       `json:"severity"`
+	Code     DiagnosticCode `json:"code"`
+	Line     int            `json:"line,omitempty"`
+	Column   int            `json:"column,omitempty"`
+	RuleID   string         `json:"ruleId,omitempty"`
+	Message  string         `json:"message"`
+}
+
+func (d Diagnostic) Error() string {
+	msg := fmt.Sprintf("%s: %s [%s]", d.Severity, d.Message, d.Code)
+	if d.RuleID != "" {
+		msg = fmt.Sprintf("%s: %s", d.RuleID, msg)
+	}
+	if d.Line > 0 {
+		msg = fmt.Sprintf("%d:%d: %s", d.Line, d.Column, msg)
+	}
+
+	return msg
+}
+
+// diagnosticsErr joins the diagnostics with error severity into an error.
+func diagnosticsErr(diagnostics []Diagnostic) error {
+	var err error
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			err = errors.Join(err, d)
+		}
+	}
+
+	return err
+}
+
+// position is a 1-based position in the source file.
+type position struct {
+	line   int
+	column int
+}
+
+type diagnostics []Diagnostic
+
+func (ds *diagnostics) add(severity Severity, code DiagnosticCode, pos position, ruleID, format string, args ...any) {
+	*ds = append(*ds, Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Line:     pos.line,
+		Column:   pos.column,
+		RuleID:   ruleID,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testRules is the smallest rules file which parses without diagnostics.
+const testRules = `Magic: The Gathering Comprehensive Rules
+
+These rules are effective as of January 16, 2026.
+
+Introduction
+
+This document is the ultimate authority for Magic competitive game play.
+
+Contents
+
+1. Game Concepts
+100. General
+
+Glossary
+
+Credits
+
+1. Game Concepts
+
+100. General
+
+100.1. These Magic rules apply to any Magic game with two or more players.
+
+100.1a A two-player game is a game that begins with only two players.
+Example: Two players play a game.
+
+Glossary
+
+Ability
+Text on an object that explains what that object does.
+
+Credits
+
+Magic: The Gathering Original Game Design: Richard Garfield
+`
+
+func TestParseDiagnostics(t *testing.T) {
+	if _, ds, err := Parse(strings.NewReader(testRules)); err != nil || len(ds) != 0 {
+		t.Fatalf("Parse returned %v, %v", ds, err)
+	}
+
+	tests := map[string]struct {
+		old, new string
+		expected []Diagnostic
+	}{
+		"no effective date": {
+			"effective as of January 16, 2026.", "effective soon.",
+			[]Diagnostic{{Severity: SeverityError, Code: CodeNoEffectiveDate}},
+		},
+		"continuation": {
+			"players.\n\n100.1a", "players.\n\nThis paragraph has no number.\n\n100.1a",
+			[]Diagnostic{{Severity: SeverityWarning, Code: CodeContinuation, Line: 24, Column: 1, RuleID: "100.1."}},
+		},
+		"invalid number": {
+			"100.1a A", "100.0. A",
+			[]Diagnostic{{Severity: SeverityError, Code: CodeInvalidNumber, Line: 24, Column: 1}},
+		},
+		"body after examples": {
+			"play a game.\n", "play a game.\nNot an example.\n",
+			[]Diagnostic{{Severity: SeverityError, Code: CodeBodyAfterExamples, Line: 26, Column: 1, RuleID: "100.1a"}},
+		},
+		"empty body": {
+			"100.1a A two-player game is a game that begins with only two players.\n", "100.1a Example: Two players.\n",
+			[]Diagnostic{{Severity: SeverityError, Code: CodeEmptyBody, Line: 24, Column: 1, RuleID: "100.1a"}},
+		},
+		"invalid heading": {
+			"\n100. General\n\n100.1.", "\n100. General\nGeneral rules\n\n100.1.",
+			[]Diagnostic{
+				{Severity: SeverityError, Code: CodeInvalidHeading, Line: 20, Column: 1, RuleID: "100."},
+				{Severity: SeverityWarning, Code: CodeContentsMismatch, Line: 12, Column: 1, RuleID: "100."},
+			},
+		},
+		"glossary item without body": {
+			"Ability\nText on an object that explains what that object does.\n", "Ability\n",
+			[]Diagnostic{{Severity: SeverityError, Code: CodeGlossaryItemNoBody, Line: 29, Column: 1}},
+		},
+		"unknown redirect": {
+			"Text on an object that explains what that object does.", "See Nothing.",
+			[]Diagnostic{{Severity: SeverityWarning, Code: CodeUnknownRedirect, Line: 29, Column: 1}},
+		},
+		"contents mismatch": {
+			"100. General\n\nGlossary", "100. Generalities\n\nGlossary",
+			[]Diagnostic{{Severity: SeverityWarning, Code: CodeContentsMismatch, Line: 12, Column: 1, RuleID: "100."}},
+		},
+	}
+
+	for name, test := range tests {
+		input := strings.Replace(testRules, test.old, test.new, 1)
+		if input == testRules {
+			t.Fatalf("%s: %q not found in the test rules", name, test.old)
+		}
+
+		// Positions must not depend on the line endings of the file.
+		for _, newline := range []string{"\n", "\r\n", "\r"} {
+			_, ds, _ := Parse(strings.NewReader(strings.ReplaceAll(input, "\n", newline)))
+			for i := range ds {
+				ds[i].Message = ""
+			}
+
+			if !slices.Equal(ds, test.expected) {
+				t.Errorf("%s with %q line endings: Parse returned diagnostics %v, expected %v", name, newline, ds, test.expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package parser
+
+import "slices"
+
+type ChangeType string
+
+const (
+	Added    ChangeType = "Added"
+	Removed  ChangeType = "Removed"
+	Modified ChangeType = "Modified"
+)
+
+// Change describes how a single section differs between two versions of the
+// rules. Old is nil for added sections and New is nil for removed sections.
+type Change struct {
+	Type ChangeType
+	ID   string
+	Old  *Section `json:",omitempty"`
+	New  *Section `json:",omitempty"`
+}
+
+// Diff returns the sections which were added, removed or modified between the
+// old and new rules. Sections are matched by their ID, added and modified
+// sections are returned in the order of the new rules followed by the removed
+// sections in the order of the old rules.
+func Diff(old, new Rules) []Change {
+	oldByID := make(map[string]*Section, len(old.Rules))
+	for i := range old.Rules {
+		oldByID[old.Rules[i].ID] = &old.Rules[i]
+	}
+
+	newByID := make(map[string]*Section, len(new.Rules))
+	for i := range new.Rules {
+		newByID[new.Rules[i].ID] = &new.Rules[i]
+	}
+
+	var out []Change
+	for i := range new.Rules {
+		newSection := &new.Rules[i]
+
+		oldSection, ok := oldByID[newSection.ID]
+		if !ok {
+			out = append(out, Change{Added, newSection.ID, nil, newSection})
+			continue
+		}
+
+		if !slices.Equal(oldSection.Body, newSection.Body) ||
+			!slices.Equal(oldSection.Examples, newSection.Examples) {
+			out = append(out, Change{Modified, newSection.ID, oldSection, newSection})
+		}
+	}
+
+	for i := range old.Rules {
+		oldSection := &old.Rules[i]
+		if _, ok := newByID[oldSection.ID]; !ok {
+			out = append(out, Change{Removed, oldSection.ID, oldSection, nil})
+		}
+	}
+
+	return out
+}
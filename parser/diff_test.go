@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	section := func(id string, body ...string) Section {
+		return Section{ID: id, Body: body}
+	}
+
+	tests := map[string]struct {
+		old, new []Section
+		expected []Change
+	}{
+		"unchanged": {
+			[]Section{section("100.1.", "Rule.")},
+			[]Section{section("100.1.", "Rule.")},
+			nil,
+		},
+		"added": {
+			[]Section{section("100.1.", "Rule.")},
+			[]Section{section("100.1.", "Rule."), section("100.2.", "New rule.")},
+			[]Change{{Type: Added, ID: "100.2."}},
+		},
+		"removed": {
+			[]Section{section("100.1.", "Rule."), section("100.2.", "Old rule.")},
+			[]Section{section("100.1.", "Rule.")},
+			[]Change{{Type: Removed, ID: "100.2."}},
+		},
+		"modified body": {
+			[]Section{section("100.1.", "Rule.")},
+			[]Section{section("100.1.", "Changed rule.")},
+			[]Change{{Type: Modified, ID: "100.1."}},
+		},
+		"modified examples": {
+			[]Section{{ID: "100.1.", Body: []string{"Rule."}, Examples: []string{"Example."}}},
+			[]Section{{ID: "100.1.", Body: []string{"Rule."}}},
+			[]Change{{Type: Modified, ID: "100.1."}},
+		},
+		// Sections are matched by their ID, so a renumbered rule is removed
+		// under its old ID and added under its new one, and the rule now in
+		// its old place is modified.
+		"renumbered": {
+			[]Section{section("100.1.", "First."), section("100.2.", "Second.")},
+			[]Section{section("100.1.", "Inserted."), section("100.2.", "First."), section("100.3.", "Second.")},
+			[]Change{{Type: Modified, ID: "100.1."}, {Type: Modified, ID: "100.2."}, {Type: Added, ID: "100.3."}},
+		},
+		"renumbered without a replacement": {
+			[]Section{section("100.1.", "Rule."), section("100.2.", "Rule.")},
+			[]Section{section("100.1.", "Rule."), section("100.3.", "Rule.")},
+			[]Change{{Type: Added, ID: "100.3."}, {Type: Removed, ID: "100.2."}},
+		},
+	}
+
+	for name, test := range tests {
+		changes := Diff(Rules{Rules: test.old}, Rules{Rules: test.new})
+
+		for i, change := range changes {
+			if (change.Old == nil) != (change.Type == Added) || (change.New == nil) != (change.Type == Removed) {
+				t.Errorf("%s: change %d %s %s has old %v and new %v", name, i, change.Type, change.ID, change.Old, change.New)
+			}
+
+			changes[i].Old, changes[i].New = nil, nil
+		}
+
+		if !slices.Equal(changes, test.expected) {
+			t.Errorf("%s: Diff = %v, expected %v", name, changes, test.expected)
+		}
+	}
+}
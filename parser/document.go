@@ -0,0 +1,225 @@
+package parser
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SectionNumber is the number of a section of the tournament rules or the
+// infraction procedure guide, e.g. "2." or "3.10". Section is 0 for the top
+// level sections.
+type SectionNumber struct {
+	Chapter int
+	Section int
+}
+
+var sectionNumberRegexp = regexp.MustCompile(`^(\d+)(?:\.?|\.(\d+)\.?)$`)
+
+// ParseSectionNumber parses the number of a section of the tournament rules
+// or the infraction procedure guide, the trailing period may be left out.
+func ParseSectionNumber(s string) (SectionNumber, error) {
+	matches := sectionNumberRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return SectionNumber{}, fmt.Errorf("invalid section number %q", s)
+	}
+
+	var n SectionNumber
+	n.Chapter, _ = strconv.Atoi(matches[1])
+	n.Section, _ = strconv.Atoi(matches[2])
+	if n.Chapter == 0 || matches[2] != "" && n.Section == 0 {
+		return SectionNumber{}, fmt.Errorf("invalid section number %q", s)
+	}
+
+	return n, nil
+}
+
+// String returns the number in the form used as the ID of sections.
+func (n SectionNumber) String() string {
+	if n.Section == 0 {
+		return fmt.Sprintf("%d.", n.Chapter)
+	}
+
+	return fmt.Sprintf("%d.%d", n.Chapter, n.Section)
+}
+
+// Compare returns -1, 0 or 1 depending on whether n comes before, is the same
+// as or comes after other in the document.
+func (n SectionNumber) Compare(other SectionNumber) int {
+	return cmp.Or(cmp.Compare(n.Chapter, other.Chapter), cmp.Compare(n.Section, other.Section))
+}
+
+// follows reports whether n is the number of the section directly after the
+// section of prev, the top level section may be left out before its first
+// section.
+func (n SectionNumber) follows(prev SectionNumber) bool {
+	switch {
+	case n.Chapter == prev.Chapter:
+		return n.Section == prev.Section+1
+	case n.Chapter == prev.Chapter+1:
+		return n.Section <= 1
+	}
+
+	return false
+}
+
+// DocumentSection is a section of the tournament rules or the infraction
+// procedure guide.
+type DocumentSection struct {
+	ID    string      `json:"id" doc:"Number of the section, e.g. \"2.\" or \"3.10\"."`
+	Title string      `json:"title" doc:"Title of the section."`
+	Body  []string    `json:"body" doc:"Paragraphs of the section."`
+	Refs  []Reference `json:"refs" doc:"References to other sections and documents in the order they appear in the body."`
+}
+
+// Document is the tournament rules or the infraction procedure guide.
+type Document struct {
+	Kind          DocumentKind      `json:"kind" doc:"Which document this is."`
+	EffectiveDate time.Time         `json:"effectiveDate" doc:"Date from which the document is in effect."`
+	Sections      []DocumentSection `json:"sections" doc:"Sections of the document in document order."`
+}
+
+// Section returns the section with the given ID.
+func (d Document) Section(id string) (DocumentSection, bool) {
+	if n, err := ParseSectionNumber(id); err == nil {
+		id = n.String()
+	}
+
+	for _, section := range d.Sections {
+		if section.ID == id {
+			return section, true
+		}
+	}
+
+	return DocumentSection{}, false
+}
+
+var (
+	documentHeadingRegexp = regexp.MustCompile(`^(\d{1,2}\.(?:\d{1,2}\.?)?)\s+(\S.*)$`)
+	// Lines of the table of contents end with the page number.
+	documentContentsRegexp = regexp.MustCompile(`(?:\.{2,}|\s)\s*\d+$`)
+	pageNumberRegexp       = regexp.MustCompile(`^(?:Page )?\d+(?: of \d+)?$`)
+)
+
+// headingNumber returns the number of the line if it is a possible heading.
+func headingNumber(line string) (n SectionNumber, title string, ok bool) {
+	matches := documentHeadingRegexp.FindStringSubmatch(line)
+	if matches == nil || documentContentsRegexp.MatchString(line) {
+		return SectionNumber{}, "", false
+	}
+
+	n, err := ParseSectionNumber(matches[1])
+	if err != nil {
+		return SectionNumber{}, "", false
+	}
+
+	return n, strings.TrimSpace(matches[2]), true
+}
+
+// isHeading reports whether the line at i with the number is a heading. The
+// number has to be the one after the previous heading, and a top level
+// heading has to be followed by its first section before any other numbered
+// line. This leaves out numbered lists in the body.
+func isHeading(lines []string, i int, n, prev SectionNumber) bool {
+	if !n.follows(prev) {
+		return false
+	}
+
+	if n.Section > 0 {
+		return true
+	}
+
+	for _, line := range lines[i+1:] {
+		if next, _, ok := headingNumber(line); ok {
+			return next == SectionNumber{n.Chapter, 1}
+		}
+	}
+
+	return false
+}
+
+// ParseDocument parses the tournament rules or the infraction procedure guide
+// from the text extracted from the PDF of the document.
+func ParseDocument(r io.Reader, kind DocumentKind) (Document, error) {
+	if kind != MTR && kind != IPG {
+		return Document{}, fmt.Errorf("unsupported document %q", kind)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(strings.ReplaceAll(scanner.Text(), "\f", "")))
+	}
+	if err := scanner.Err(); err != nil {
+		return Document{}, err
+	}
+
+	doc := Document{Kind: kind, Sections: []DocumentSection{}}
+
+	var (
+		prev      SectionNumber
+		paragraph []string
+	)
+
+	endParagraph := func() {
+		if len(paragraph) == 0 || len(doc.Sections) == 0 {
+			paragraph = nil
+			return
+		}
+
+		section := &doc.Sections[len(doc.Sections)-1]
+		section.Body = append(section.Body, strings.Join(paragraph, " "))
+		paragraph = nil
+	}
+
+	for i, line := range lines {
+		if doc.EffectiveDate.IsZero() && strings.Contains(line, "Effective") {
+			doc.EffectiveDate, _ = localizations[English].parseDate(line)
+		}
+
+		if line == "" {
+			endParagraph()
+			continue
+		}
+
+		if pageNumberRegexp.MatchString(line) {
+			continue
+		}
+
+		if n, title, ok := headingNumber(line); ok && isHeading(lines, i, n, prev) {
+			endParagraph()
+			doc.Sections = append(doc.Sections, DocumentSection{
+				ID:    n.String(),
+				Title: title,
+				Body:  []string{},
+			})
+			prev = n
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+	}
+	endParagraph()
+
+	if len(doc.Sections) == 0 {
+		return Document{}, errors.New("failed to parse any sections from the document")
+	}
+
+	for i := range doc.Sections {
+		section := &doc.Sections[i]
+		section.Refs = []Reference{}
+		for _, paragraph := range section.Body {
+			for _, span := range FindReferences(kind, paragraph) {
+				section.Refs = append(section.Refs, span.Reference)
+			}
+		}
+	}
+
+	return doc, nil
+}
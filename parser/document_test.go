@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testDocument = `Magic: The Gathering Tournament Rules
+Effective February 5, 2024
+
+1. Tournament Fundamentals ........ 5
+1.1 Tournament Types ........ 5
+
+1. Tournament Fundamentals
+1.1 Tournament Types
+Tournaments are described in section 1.2 and the
+Infraction Procedure Guide.
+
+1.2 Publishing Tournament Information
+The following are published:
+1. The date
+2. The location
+
+12
+
+2. Tournament Mechanics
+2.1 Match Structure
+See IPG 3.10 and rule 702.19b.
+`
+
+func TestParseDocument(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(testDocument), MTR)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.EffectiveDate.Format("2006-01-02") != "2024-02-05" {
+		t.Errorf("EffectiveDate = %s, expected 2024-02-05", doc.EffectiveDate)
+	}
+
+	var ids []string
+	for _, section := range doc.Sections {
+		ids = append(ids, section.ID)
+	}
+	if expected := []string{"1.", "1.1", "1.2", "2.", "2.1"}; !slices.Equal(ids, expected) {
+		t.Fatalf("Sections = %v, expected %v", ids, expected)
+	}
+
+	section, _ := doc.Section("1.2")
+	if expected := []string{"The following are published: 1. The date 2. The location"}; !slices.Equal(section.Body, expected) {
+		t.Errorf("Body of 1.2 = %q, expected %q", section.Body, expected)
+	}
+
+	section, _ = doc.Section("1.1")
+	if expected := []Reference{{MTR, "1.2"}, {IPG, ""}}; !slices.Equal(section.Refs, expected) {
+		t.Errorf("Refs of 1.1 = %v, expected %v", section.Refs, expected)
+	}
+
+	section, _ = doc.Section("2.1")
+	if expected := []Reference{{IPG, "3.10"}, {CR, "702.19b"}}; !slices.Equal(section.Refs, expected) {
+		t.Errorf("Refs of 2.1 = %v, expected %v", section.Refs, expected)
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	tests := map[string]Reference{
+		"MTR 2.4":    {MTR, "2.4"},
+		"ipg 3.10.":  {IPG, "3.10"},
+		"CR 702.19B": {CR, "702.19b"},
+		"IPG":        {IPG, ""},
+	}
+
+	for input, expected := range tests {
+		ref, err := ParseReference(input)
+		if err != nil || ref != expected {
+			t.Errorf("ParseReference(%q) = %v, %v, expected %v", input, ref, err, expected)
+		}
+
+		if again, _ := ParseReference(ref.String()); again != ref {
+			t.Errorf("ParseReference(%q) = %v, expected %v", ref.String(), again, ref)
+		}
+	}
+
+	for _, input := range []string{"", "XYZ 1.1", "MTR 0.1", "CR 70"} {
+		if _, err := ParseReference(input); err == nil {
+			t.Errorf("ParseReference(%q) expected an error", input)
+		}
+	}
+}
+
+func TestReferenceURL(t *testing.T) {
+	tests := map[Reference]string{
+		{MTR, "2.4"}:    "https://rulesraker.com/mtr.html#2.4",
+		{IPG, ""}:       "https://rulesraker.com/ipg.html",
+		{CR, "702.19b"}: "https://rulesraker.com/#702.19b",
+		{CR, "100.1."}:  "https://rulesraker.com/#100.1.",
+	}
+
+	for ref, expected := range tests {
+		if url := ref.URL(); url != expected {
+			t.Errorf("%v.URL() = %q, expected %q", ref, url, expected)
+		}
+	}
+}
@@ -1,9 +1,8 @@
 package parser
 
 import (
-	"errors"
-	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -30,42 +29,114 @@ func glossaryID(s string) string {
 }
 
 type GlossaryItem struct {
-	ID       string
-	KeyText  string
-	KeyParts []string
-	Body     string
+	ID       string   `json:"id" doc:"Identifier derived from the first key part, e.g. \"first-strike\"."`
+	KeyText  string   `json:"keyText" doc:"Term of the glossary item as written in the rules."`
+	KeyParts []string `json:"keyParts" doc:"Alternative names of the term."`
+	Body     string   `json:"body" doc:"Definition of the term."`
+	Obsolete bool     `json:"obsolete" doc:"Whether the term is marked as obsolete."`
+	Redirect string   `json:"redirect,omitempty" doc:"ID of the glossary item the item only refers to with \"See ...\"."`
+	Senses   []string `json:"senses" doc:"Numbered senses of the term without their numbers, empty if the term has only one sense."`
+	Refs     []string `json:"refs" doc:"IDs of the rules referenced by the definition."`
 }
 
+var (
+	glossarySenseRegexp    = regexp.MustCompile(`^\d+\. `)
+	glossaryRedirectRegexp = regexp.MustCompile(`^See ([^\n]+)\.$`)
+)
+
 func newGlossaryItem(key, body string) GlossaryItem {
+	obsolete := strings.Contains(key, "(Obsolete)")
+
 	var parts []string
-	if key == "Active Player, Nonactive Player Order" {
-		parts = append(parts, key)
-	} else {
-		for _, part := range strings.Split(key, ",") {
-			part = strings.TrimSpace(strings.ReplaceAll(part, "(Obsolete)", ""))
-			parts = append(parts, part)
+	for _, part := range strings.Split(key, ",") {
+		part = strings.TrimSpace(strings.ReplaceAll(part, "(Obsolete)", ""))
+		parts = append(parts, part)
+	}
+
+	senses := []string{}
+	for _, line := range strings.Split(body, "\n") {
+		if number := glossarySenseRegexp.FindString(line); number != "" {
+			senses = append(senses, line[len(number):])
 		}
 	}
 
 	id := glossaryID(parts[0])
 
-	return GlossaryItem{id, key, parts, body}
+	return GlossaryItem{
+		ID:       id,
+		KeyText:  key,
+		KeyParts: parts,
+		Body:     body,
+		Obsolete: obsolete,
+		Senses:   senses,
+		Refs:     []string{},
+	}
 }
 
-func parseGlossary(items []string) ([]GlossaryItem, error) {
+// joinSingleTerms joins back the key parts of the items whose key has a comma
+// but is a single term instead of a list of alternative names, e.g. "Active
+// Player, Nonactive Player Order". A key is a single term when another item
+// refers to the whole key with "See ...", like "APNAP Order" does.
+func joinSingleTerms(items []GlossaryItem) {
+	referred := make(map[string]bool)
+	for _, item := range items {
+		if match := glossaryRedirectRegexp.FindStringSubmatch(item.Body); match != nil {
+			referred[match[1]] = true
+		}
+	}
+
+	for i := range items {
+		item := &items[i]
+		if len(item.KeyParts) < 2 || !referred[item.KeyText] {
+			continue
+		}
+
+		key := strings.TrimSpace(strings.ReplaceAll(item.KeyText, "(Obsolete)", ""))
+		item.ID = glossaryID(key)
+		item.KeyParts = []string{key}
+	}
+}
+
+func parseGlossary(items []block, rules []Section) ([]GlossaryItem, diagnostics) {
 	var (
-		err error
-		out []GlossaryItem
+		ds        diagnostics
+		out       []GlossaryItem
+		positions []position
 	)
 	for _, item := range items {
-		splitted := strings.SplitN(item, "\n", 2)
+		splitted := strings.SplitN(item.text, "\n", 2)
 		if len(splitted) != 2 {
-			err = errors.Join(err, fmt.Errorf("glossary item with no body: %q", item))
+			ds.add(SeverityError, CodeGlossaryItemNoBody, item.pos(), "", "glossary item with no body: %q", item.text)
 			continue
 		}
 
 		out = append(out, newGlossaryItem(splitted[0], splitted[1]))
+		positions = append(positions, item.pos())
+	}
+
+	joinSingleTerms(out)
+
+	// References can only be resolved once all of the items are known.
+	t := NewTokenizer(Rules{Rules: rules, Glossary: out})
+	for i := range out {
+		item := &out[i]
+
+		for _, inline := range t.Tokenize(item.Body) {
+			if inline.Kind == InlineRuleRef && !slices.Contains(item.Refs, inline.Target) {
+				item.Refs = append(item.Refs, inline.Target)
+			}
+		}
+
+		if match := glossaryRedirectRegexp.FindStringSubmatch(item.Body); match != nil && !strings.HasPrefix(match[1], "rule ") {
+			id, ok := t.glossary[strings.ToLower(match[1])]
+			if !ok {
+				ds.add(SeverityWarning, CodeUnknownRedirect, positions[i], "", "glossary item %q refers to unknown term %q", item.KeyText, match[1])
+				continue
+			}
+
+			item.Redirect = id
+		}
 	}
 
-	return out, err
+	return out, ds
 }
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testGlossary = `Ability
+1. Text on an object that explains what that object does.
+2. An activated or triggered ability on the stack.
+See rule 100.1, “General.”
+
+Active Player
+The player whose turn it is.
+
+Active Player, Nonactive Player Order
+A system that determines the order by which players make choices.
+
+APNAP Order
+See Active Player, Nonactive Player Order.
+
+Control, Controller
+“Control” is the system that determines who gets to use an object. See rules 100.1 and 100.1a.
+
+Remove from the Game, Removed (Obsolete)
+An obsolete term for exile.
+
+Removed
+See Remove from the Game.
+`
+
+func TestParseGlossary(t *testing.T) {
+	input := strings.Replace(testRules, "Ability\nText on an object that explains what that object does.\n", testGlossary, 1)
+
+	rules, ds, err := Parse(strings.NewReader(input))
+	if err != nil || len(ds) != 0 {
+		t.Fatalf("Parse returned %v, %v", ds, err)
+	}
+
+	expected := []GlossaryItem{
+		{
+			ID: "ability", KeyParts: []string{"Ability"},
+			Senses: []string{"Text on an object that explains what that object does.", "An activated or triggered ability on the stack."},
+			Refs:   []string{"100.1."},
+		},
+		{ID: "active-player", KeyParts: []string{"Active Player"}, Senses: []string{}, Refs: []string{}},
+		{ID: "active-player-nonactive-player-order", KeyParts: []string{"Active Player, Nonactive Player Order"}, Senses: []string{}, Refs: []string{}},
+		{ID: "apnap-order", KeyParts: []string{"APNAP Order"}, Redirect: "active-player-nonactive-player-order", Senses: []string{}, Refs: []string{}},
+		{ID: "control", KeyParts: []string{"Control", "Controller"}, Senses: []string{}, Refs: []string{"100.1.", "100.1a"}},
+		{ID: "remove-from-the-game", KeyParts: []string{"Remove from the Game", "Removed"}, Obsolete: true, Senses: []string{}, Refs: []string{}},
+		{ID: "removed", KeyParts: []string{"Removed"}, Redirect: "remove-from-the-game", Senses: []string{}, Refs: []string{}},
+	}
+
+	if len(rules.Glossary) != len(expected) {
+		t.Fatalf("Parse returned %d glossary items, expected %d", len(rules.Glossary), len(expected))
+	}
+
+	for i, item := range rules.Glossary {
+		item.KeyText = ""
+		item.Body = ""
+		if !reflect.DeepEqual(item, expected[i]) {
+			t.Errorf("glossary item %d = %+v, expected %+v", i, item, expected[i])
+		}
+	}
+}
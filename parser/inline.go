@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+type InlineKind string
+
+const (
+	InlineText         InlineKind = "Text"
+	InlineSymbol       InlineKind = "Symbol"
+	InlineRuleRef      InlineKind = "RuleRef"
+	InlineGlossaryTerm InlineKind = "GlossaryTerm"
+	InlineLink         InlineKind = "Link"
+	InlineDocumentRef  InlineKind = "DocumentRef"
+	InlineCard         InlineKind = "Card"
+)
+
+// Inline is a span of rules text. Target is the ID of the referenced rule for
+// rule references, the ID of the glossary item for glossary terms, the URL for
+// links, the reference as written by Reference.String for references to other
+// documents and the name of the card for card names.
+type Inline struct {
+	Kind   InlineKind `json:"kind"`
+	Text   string     `json:"text"`
+	Target string     `json:"target,omitempty"`
+}
+
+var inlineRegexp = regexp.MustCompile(
+	`(?P<symbol>\{[^{}\s]+\})` +
+		`|(?P<link>(?i:[a-z.]+\.com[a-z/-]*))` +
+		`|section (?P<section>\d)\b` +
+		`|\b(?P<rule>(?P<number>\d{3}(?:\.\d+[a-z]*)?)(?:–(?:\d+|\w)+)?)` +
+		`|(?P<document>` + documentNamesPattern() + `)` +
+		`|(?P<quote>“[^”]+”)`,
+)
+
+// Tokenizer splits rules text into inline spans. Only references to rules and
+// glossary items which exist in the rules are recognized, and only the card
+// names detected in the rules by Rules.DetectCards.
+type Tokenizer struct {
+	rules    map[string]bool
+	glossary map[string]string
+	cards    *CardMatcher
+}
+
+func NewTokenizer(rules Rules) *Tokenizer {
+	t := &Tokenizer{
+		rules:    make(map[string]bool, len(rules.Rules)),
+		glossary: make(map[string]string),
+	}
+
+	var cards []string
+	for _, section := range rules.Rules {
+		t.rules[section.ID] = true
+		cards = append(cards, section.Cards...)
+	}
+	t.cards = NewCardMatcher(cards)
+
+	for _, item := range rules.Glossary {
+		for _, part := range item.KeyParts {
+			t.glossary[strings.ToLower(part)] = item.ID
+		}
+	}
+
+	return t
+}
+
+func appendText(out []Inline, text string) []Inline {
+	if text == "" {
+		return out
+	}
+
+	if len(out) > 0 && out[len(out)-1].Kind == InlineText {
+		out[len(out)-1].Text += text
+		return out
+	}
+
+	return append(out, Inline{Kind: InlineText, Text: text})
+}
+
+// match returns the inline of a match of inlineRegexp, ok is false when the
+// match is not recognized.
+func (t *Tokenizer) match(s string, match []int) (Inline, bool) {
+	group := func(name string) string {
+		i := 2 * inlineRegexp.SubexpIndex(name)
+		if match[i] < 0 {
+			return ""
+		}
+
+		return s[match[i]:match[i+1]]
+	}
+
+	text := s[match[0]:match[1]]
+
+	switch {
+	case group("symbol") != "":
+		return Inline{Kind: InlineSymbol, Text: text}, true
+	case group("link") != "":
+		return Inline{Kind: InlineLink, Text: text, Target: "https://" + text}, true
+	case group("section") != "", group("rule") != "":
+		// A range such as "601.2a–f" refers to the first rule of the range.
+		n, err := ParseRuleNumber(group("section") + group("number"))
+		if err != nil {
+			return Inline{}, false
+		}
+
+		id := n.String()
+		return Inline{Kind: InlineRuleRef, Text: text, Target: id}, t.rules[id]
+	case group("document") != "":
+		ref := Reference{Document: documentNames[text]}
+		return Inline{Kind: InlineDocumentRef, Text: text, Target: ref.String()}, ref.Document != CR
+	}
+
+	term := strings.TrimRight(strings.Trim(text, "“”"), ".,;:")
+	id, ok := t.glossary[strings.ToLower(term)]
+	return Inline{Kind: InlineGlossaryTerm, Text: text, Target: id}, ok
+}
+
+// splitCards splits the card names out of the text spans.
+func (t *Tokenizer) splitCards(inlines []Inline) []Inline {
+	var out []Inline
+	for _, inline := range inlines {
+		if inline.Kind != InlineText {
+			out = append(out, inline)
+			continue
+		}
+
+		last := 0
+		for _, span := range t.cards.find(inline.Text) {
+			out = appendText(out, inline.Text[last:span.start])
+			out = append(out, Inline{Kind: InlineCard, Text: inline.Text[span.start:span.end], Target: span.name})
+			last = span.end
+		}
+		out = appendText(out, inline.Text[last:])
+	}
+
+	return out
+}
+
+// Tokenize splits the text into inline spans, the text of the spans joined
+// together is the original text.
+func (t *Tokenizer) Tokenize(s string) []Inline {
+	var out []Inline
+
+	last := 0
+	for _, match := range inlineRegexp.FindAllStringSubmatchIndex(s, -1) {
+		start, end := match[0], match[1]
+
+		inline, ok := t.match(s, match)
+		if !ok {
+			if inline.Kind == InlineGlossaryTerm {
+				// Quoted text which is not a glossary term can still contain
+				// other spans.
+				out = appendText(out, s[last:start]+"“")
+				for _, inner := range t.Tokenize(s[start+len("“") : end]) {
+					if inner.Kind == InlineText {
+						out = appendText(out, inner.Text)
+					} else {
+						out = append(out, inner)
+					}
+				}
+				last = end
+			}
+
+			continue
+		}
+
+		out = appendText(out, s[last:start])
+		out = append(out, inline)
+		last = end
+	}
+
+	return t.splitCards(appendText(out, s[last:]))
+}
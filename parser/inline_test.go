@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tokenizer := NewTokenizer(Rules{
+		Rules: []Section{
+			{ID: "1."},
+			{ID: "100.1."},
+			{ID: "601.2a"},
+			{ID: "702.19b", Cards: []string{"Llanowar Elves", "Ability"}},
+		},
+		Glossary: []GlossaryItem{
+			{ID: "ability", KeyParts: []string{"Ability"}},
+			{ID: "active-player", KeyParts: []string{"Active Player"}},
+		},
+	})
+
+	text := func(s string) Inline { return Inline{Kind: InlineText, Text: s} }
+
+	tests := map[string][]Inline{
+		"See rule 100.1.": {text("See rule "), {InlineRuleRef, "100.1", "100.1."}, text(".")},
+		"See rules 601.2a–f.": {
+			text("See rules "), {InlineRuleRef, "601.2a–f", "601.2a"}, text("."),
+		},
+		"See section 1.":      {text("See "), {InlineRuleRef, "section 1", "1."}, text(".")},
+		"Rule 999.9 is gone.": {text("Rule 999.9 is gone.")},
+		"Add {G}{1}.":         {text("Add "), {InlineSymbol, "{G}", ""}, {InlineSymbol, "{1}", ""}, text(".")},
+		"The “active player” acts.": {
+			text("The "), {InlineGlossaryTerm, "“active player”", "active-player"}, text(" acts."),
+		},
+		"Visit Magic.Wizards.com/Rules.": {
+			text("Visit "), {InlineLink, "Magic.Wizards.com/Rules", "https://Magic.Wizards.com/Rules"}, text("."),
+		},
+		"See the Magic Tournament Rules.": {
+			text("See the "), {InlineDocumentRef, "Magic Tournament Rules", "MTR"}, text("."),
+		},
+		"These Comprehensive Rules apply.": {text("These Comprehensive Rules apply.")},
+		"Llanowar Elves taps.":             {{InlineCard, "Llanowar Elves", "Llanowar Elves"}, text(" taps.")},
+		// Links can't contain digits, so a rule number after a link is a
+		// reference of its own.
+		"See Wizards.com/rules/100.1": {
+			text("See "), {InlineLink, "Wizards.com/rules/", "https://Wizards.com/rules/"}, {InlineRuleRef, "100.1", "100.1."},
+		},
+		// Quoted text which is not a glossary term is tokenized further.
+		"“Follow rule 100.1”": {text("“Follow rule "), {InlineRuleRef, "100.1", "100.1."}, text("”")},
+		// A quoted glossary term is not a card even when the term is also the
+		// name of a card.
+		"“Ability” or Ability": {
+			{InlineGlossaryTerm, "“Ability”", "ability"}, text(" or "), {InlineCard, "Ability", "Ability"},
+		},
+		// Cards are found in quoted text which is not a glossary term.
+		"“Llanowar Elves, 100.1”": {
+			text("“"), {InlineCard, "Llanowar Elves", "Llanowar Elves"}, text(", "), {InlineRuleRef, "100.1", "100.1."}, text("”"),
+		},
+	}
+
+	for input, expected := range tests {
+		inlines := tokenizer.Tokenize(input)
+		if !slices.Equal(inlines, expected) {
+			t.Errorf("Tokenize(%q) = %v, expected %v", input, inlines, expected)
+		}
+
+		var joined strings.Builder
+		for _, inline := range inlines {
+			joined.WriteString(inline.Text)
+		}
+		if joined.String() != input {
+			t.Errorf("Tokenize(%q) joined together is %q", input, joined.String())
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+type KeywordKind string
+
+const (
+	KeywordAction  KeywordKind = "Action"
+	KeywordAbility KeywordKind = "Ability"
+)
+
+// keywordChapters maps the chapters of keywords to their kind, the first rule
+// of both of the chapters is a general rule and not a keyword.
+var keywordChapters = map[int]KeywordKind{
+	701: KeywordAction,
+	702: KeywordAbility,
+}
+
+var (
+	keywordReminderRegexp  = regexp.MustCompile(`“[^”]+” means “([^”]+)”`)
+	keywordRedundantRegexp = regexp.MustCompile(`Multiple instances of .+ are redundant`)
+)
+
+type Keyword struct {
+	Name       string      `json:"name" doc:"Name of the keyword, e.g. \"Deathtouch\"."`
+	Kind       KeywordKind `json:"kind" doc:"Whether the keyword is a keyword action or a keyword ability."`
+	RuleID     string      `json:"ruleId" doc:"ID of the rule of the keyword, e.g. \"702.2.\"."`
+	Subrules   []string    `json:"subrules" doc:"IDs of the subrules defining the keyword."`
+	Reminder   string      `json:"reminder,omitempty" doc:"Meaning of the keyword in the style of reminder text."`
+	Redundant  bool        `json:"redundant" doc:"Whether multiple instances of the keyword on the same object are redundant."`
+	GlossaryID string      `json:"glossaryId,omitempty" doc:"ID of the glossary item of the keyword."`
+}
+
+// keywordNumber returns the number of the rule if it is the rule of a
+// keyword.
+func keywordNumber(section Section) (RuleNumber, bool) {
+	n, err := ParseRuleNumber(section.ID)
+	if err != nil || n.Type() != Rule {
+		return RuleNumber{}, false
+	}
+
+	_, ok := keywordChapters[n.Chapter]
+	return n, ok && n.Rule != 1
+}
+
+func parseKeywords(rules []Section, glossary []GlossaryItem) []Keyword {
+	glossaryIDs := make(map[string]string)
+	for _, item := range glossary {
+		for _, part := range item.KeyParts {
+			glossaryIDs[strings.ToLower(part)] = item.ID
+		}
+	}
+
+	var out []Keyword
+	for i, section := range rules {
+		n, ok := keywordNumber(section)
+		if !ok {
+			continue
+		}
+
+		keyword := Keyword{
+			Name:       section.Body[0],
+			Kind:       keywordChapters[n.Chapter],
+			RuleID:     section.ID,
+			Subrules:   []string{},
+			GlossaryID: glossaryIDs[strings.ToLower(section.Body[0])],
+		}
+
+		for _, subrule := range rules[i+1:] {
+			subruleNumber, err := ParseRuleNumber(subrule.ID)
+			if err != nil || !n.IsAncestorOf(subruleNumber) {
+				break
+			}
+
+			keyword.Subrules = append(keyword.Subrules, subrule.ID)
+
+			body := strings.Join(subrule.Body, " ")
+			if match := keywordReminderRegexp.FindStringSubmatch(body); match != nil && keyword.Reminder == "" {
+				keyword.Reminder = match[1]
+			}
+			if keywordRedundantRegexp.MatchString(body) {
+				keyword.Redundant = true
+			}
+		}
+
+		out = append(out, keyword)
+	}
+
+	return out
+}
+
+// IsKeyword reports whether the rule is the rule of a keyword action or a
+// keyword ability.
+func (r Rules) IsKeyword(id string) bool {
+	for _, keyword := range r.Keywords {
+		if keyword.RuleID == id {
+			return true
+		}
+	}
+
+	return false
+}
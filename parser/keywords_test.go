@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testKeywordRules = `
+7. Additional Rules
+
+701. Keyword Actions
+
+701.1. Most actions described in a card’s rules text use the standard English definitions of the verbs within.
+
+701.2. Destroy
+
+701.2a To destroy a permanent, move it from the battlefield to its owner’s graveyard.
+
+702. Keyword Abilities
+
+702.1. Most abilities describe exactly what they do in the card’s rules text.
+
+702.2. Deathtouch
+
+702.2a Deathtouch is a static ability.
+
+702.2b Multiple instances of deathtouch on the same object are redundant.
+
+702.3. Flying
+
+702.3a Flying is an evasion ability.
+
+702.3b “Flying” means “This creature can’t be blocked except by creatures with flying or reach.”
+
+Glossary
+`
+
+func TestParseKeywords(t *testing.T) {
+	input := strings.NewReplacer(
+		"100. General\n\nGlossary", "100. General\n7. Additional Rules\n701. Keyword Actions\n702. Keyword Abilities\n\nGlossary",
+		"\nGlossary\n\nAbility\n", testKeywordRules+"\nAbility\n",
+		"what that object does.\n", "what that object does.\n\nDeathtouch\nA keyword ability that causes damage to be lethal. See rule 702.2, “Deathtouch.”\n",
+	).Replace(testRules)
+
+	rules, ds, err := Parse(strings.NewReader(input))
+	if err != nil || len(ds) != 0 {
+		t.Fatalf("Parse returned %v, %v", ds, err)
+	}
+
+	expected := []Keyword{
+		{Name: "Destroy", Kind: KeywordAction, RuleID: "701.2.", Subrules: []string{"701.2a"}},
+		{Name: "Deathtouch", Kind: KeywordAbility, RuleID: "702.2.", Subrules: []string{"702.2a", "702.2b"}, Redundant: true, GlossaryID: "deathtouch"},
+		{
+			Name: "Flying", Kind: KeywordAbility, RuleID: "702.3.", Subrules: []string{"702.3a", "702.3b"},
+			Reminder: "This creature can’t be blocked except by creatures with flying or reach.",
+		},
+	}
+
+	if !reflect.DeepEqual(rules.Keywords, expected) {
+		t.Errorf("Keywords = %+v, expected %+v", rules.Keywords, expected)
+	}
+
+	if !rules.IsKeyword("702.2.") || rules.IsKeyword("702.1.") || rules.IsKeyword("702.2a") {
+		t.Error("IsKeyword expected only the rules of keywords to be keywords")
+	}
+}
@@ -0,0 +1,187 @@
+package parser
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Language is the language of the rules as an ISO 639-1 code.
+type Language string
+
+const (
+	English    Language = "en"
+	French     Language = "fr"
+	German     Language = "de"
+	Italian    Language = "it"
+	Spanish    Language = "es"
+	Portuguese Language = "pt"
+	Japanese   Language = "ja"
+)
+
+// Languages are the languages the rules can be parsed in.
+var Languages = []Language{English, French, German, Italian, Spanish, Portuguese, Japanese}
+
+// localization has the words which differ between the languages of the rules.
+// Some headers have changed between versions of the translations, so any of
+// the alternatives is accepted.
+type localization struct {
+	introduction []string
+	contents     []string
+	glossary     []string
+	credits      []string
+	examples     []string
+	// date must have the named groups day, month and year. Months are the
+	// names of the months in lowercase, the month is a number when empty.
+	date   *regexp.Regexp
+	months []string
+}
+
+var localizations = map[Language]localization{
+	English: {
+		introduction: []string{"Introduction"},
+		contents:     []string{"Contents"},
+		glossary:     []string{"Glossary"},
+		credits:      []string{"Credits"},
+		examples:     []string{"Example:"},
+		date:         regexp.MustCompile(`(?P<month>\pL+) (?P<day>\d+), (?P<year>\d+)`),
+		months:       []string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"},
+	},
+	French: {
+		introduction: []string{"Introduction"},
+		contents:     []string{"Sommaire", "Table des matières"},
+		glossary:     []string{"Glossaire"},
+		credits:      []string{"Crédits"},
+		examples:     []string{"Exemple :", "Exemple:"},
+		date:         regexp.MustCompile(`(?P<day>\d+)(?:er)? (?P<month>\pL+) (?P<year>\d{4})`),
+		months:       []string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	},
+	German: {
+		introduction: []string{"Einleitung", "Einführung"},
+		contents:     []string{"Inhalt", "Inhaltsverzeichnis"},
+		glossary:     []string{"Glossar"},
+		credits:      []string{"Mitwirkende", "Impressum", "Credits"},
+		examples:     []string{"Beispiel:"},
+		date:         regexp.MustCompile(`(?P<day>\d+)\. (?P<month>\pL+) (?P<year>\d{4})`),
+		months:       []string{"januar", "februar", "märz", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "dezember"},
+	},
+	Italian: {
+		introduction: []string{"Introduzione"},
+		contents:     []string{"Indice", "Sommario"},
+		glossary:     []string{"Glossario"},
+		credits:      []string{"Riconoscimenti", "Crediti"},
+		examples:     []string{"Esempio:"},
+		date:         regexp.MustCompile(`(?P<day>\d+) (?P<month>\pL+) (?P<year>\d{4})`),
+		months:       []string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+	},
+	Spanish: {
+		introduction: []string{"Introducción"},
+		contents:     []string{"Contenido", "Índice"},
+		glossary:     []string{"Glosario"},
+		credits:      []string{"Créditos"},
+		examples:     []string{"Ejemplo:"},
+		date:         regexp.MustCompile(`(?P<day>\d+) de (?P<month>\pL+) de (?P<year>\d{4})`),
+		months:       []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	},
+	Portuguese: {
+		introduction: []string{"Introdução"},
+		contents:     []string{"Sumário", "Índice", "Conteúdo"},
+		glossary:     []string{"Glossário"},
+		credits:      []string{"Créditos"},
+		examples:     []string{"Exemplo:"},
+		date:         regexp.MustCompile(`(?P<day>\d+) de (?P<month>\pL+) de (?P<year>\d{4})`),
+		months:       []string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+	},
+	Japanese: {
+		introduction: []string{"はじめに", "序文"},
+		contents:     []string{"目次"},
+		glossary:     []string{"用語集"},
+		credits:      []string{"クレジット"},
+		examples:     []string{"例：", "例:"},
+		date:         regexp.MustCompile(`(?P<year>\d{4})年(?P<month>\d+)月(?P<day>\d+)日`),
+	},
+}
+
+func isHeader(headers []string, s string) bool {
+	return slices.ContainsFunc(headers, func(header string) bool {
+		return strings.EqualFold(header, s)
+	})
+}
+
+// parseDate parses the first date written in the language in s.
+func (l localization) parseDate(s string) (time.Time, bool) {
+	matches := l.date.FindStringSubmatch(s)
+	if matches == nil {
+		return time.Time{}, false
+	}
+
+	group := func(name string) string {
+		return matches[l.date.SubexpIndex(name)]
+	}
+
+	month := slices.Index(l.months, strings.ToLower(group("month"))) + 1
+	if l.months == nil {
+		month, _ = strconv.Atoi(group("month"))
+	}
+
+	day, _ := strconv.Atoi(group("day"))
+	year, _ := strconv.Atoi(group("year"))
+
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if month < 1 || month > 12 || t.Day() != day {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// example returns the text of the example without the prefix, ok is false
+// when the line is not an example.
+func (l localization) example(line string) (example string, ok bool) {
+	for _, prefix := range l.examples {
+		if rest, ok := strings.CutPrefix(line, prefix); ok {
+			return strings.TrimSpace(rest), true
+		}
+	}
+
+	return "", false
+}
+
+// headers returns all of the section headers of the language.
+func (l localization) headers() []string {
+	return slices.Concat(l.introduction, l.contents, l.glossary, l.credits)
+}
+
+// detectLanguage returns the language with the most section headers in the
+// blocks, English when none are found. Headers shared by several languages,
+// such as "Introduction" in English and French, are not counted.
+func detectLanguage(blocks []block) Language {
+	shared := map[string]int{}
+	for _, l := range localizations {
+		for _, header := range l.headers() {
+			shared[strings.ToLower(header)]++
+		}
+	}
+
+	best, bestCount := English, 0
+	for _, language := range Languages {
+		headers := slices.DeleteFunc(localizations[language].headers(), func(header string) bool {
+			return shared[strings.ToLower(header)] > 1
+		})
+
+		count := 0
+		for _, b := range blocks {
+			if isHeader(headers, strings.TrimSpace(b.text)) {
+				count++
+			}
+		}
+
+		if count > bestCount {
+			best, bestCount = language, count
+		}
+	}
+
+	return best
+}
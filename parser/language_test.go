@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLocalizedDate(t *testing.T) {
+	expected := time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC)
+
+	tests := map[Language]string{
+		English:    "These rules are effective as of January 16, 2026.",
+		French:     "Ces règles sont en vigueur à compter du 16 janvier 2026.",
+		German:     "Diese Regeln gelten ab dem 16. Januar 2026.",
+		Italian:    "Queste regole sono in vigore dal 16 gennaio 2026.",
+		Spanish:    "Estas reglas entran en vigor el 16 de enero de 2026.",
+		Portuguese: "Estas regras entram em vigor em 16 de janeiro de 2026.",
+		Japanese:   "この総合ルールは2026年1月16日より有効です。",
+	}
+
+	for language, input := range tests {
+		date, ok := localizations[language].parseDate(input)
+		if !ok || !date.Equal(expected) {
+			t.Errorf("parseDate(%q) in %s = %s, expected %s", input, language, date, expected)
+		}
+	}
+
+	if _, ok := localizations[English].parseDate("February 30, 2026"); ok {
+		t.Error("Expected an invalid date not to be parsed.")
+	}
+}
+
+func TestParseLocalized(t *testing.T) {
+	// The headers of the test rules in the order date, introduction, contents,
+	// glossary, credits and example.
+	tests := map[Language][]string{
+		English:    {"These rules are effective as of January 16, 2026.", "Introduction", "Contents", "Glossary", "Credits", "Example:"},
+		French:     {"Ces règles sont en vigueur à compter du 16 janvier 2026.", "Introduction", "Sommaire", "Glossaire", "Crédits", "Exemple :"},
+		German:     {"Diese Regeln gelten ab dem 16. Januar 2026.", "Einleitung", "Inhalt", "Glossar", "Credits", "Beispiel:"},
+		Italian:    {"Queste regole sono in vigore dal 16 gennaio 2026.", "Introduzione", "Indice", "Glossario", "Riconoscimenti", "Esempio:"},
+		Spanish:    {"Estas reglas entran en vigor el 16 de enero de 2026.", "Introducción", "Índice", "Glosario", "Créditos", "Ejemplo:"},
+		Portuguese: {"Estas regras entram em vigor em 16 de janeiro de 2026.", "Introdução", "Índice", "Glossário", "Créditos", "Exemplo:"},
+		Japanese:   {"この総合ルールは2026年1月16日より有効です。", "はじめに", "目次", "用語集", "クレジット", "例："},
+	}
+
+	for language, headers := range tests {
+		input := strings.NewReplacer(
+			"These rules are effective as of January 16, 2026.", headers[0],
+			"Introduction\n", headers[1]+"\n",
+			"Contents\n", headers[2]+"\n",
+			"Glossary\n", headers[3]+"\n",
+			"Credits\n", headers[4]+"\n",
+			"Example:", headers[5],
+		).Replace(testRules)
+
+		rules, ds, err := Parse(strings.NewReader(input))
+		if err != nil || len(ds) != 0 {
+			t.Errorf("Parse in %s returned %v, %v", language, ds, err)
+			continue
+		}
+
+		if rules.Language != language {
+			t.Errorf("Parse in %s detected the language %s", language, rules.Language)
+		}
+
+		if !rules.EffectiveDate.Equal(time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Parse in %s returned effective date %s", language, rules.EffectiveDate)
+		}
+
+		var ids []string
+		for _, section := range rules.Rules {
+			ids = append(ids, section.ID)
+		}
+		if expected := []string{"1.", "100.", "100.1.", "100.1a"}; !slices.Equal(ids, expected) {
+			t.Errorf("Parse in %s returned rules %v, expected %v", language, ids, expected)
+		}
+
+		if len(rules.Rules) == 4 && !slices.Equal(rules.Rules[3].Examples, []string{"Two players play a game."}) {
+			t.Errorf("Parse in %s returned examples %q", language, rules.Rules[3].Examples)
+		}
+
+		if len(rules.Introduction) != 1 || len(rules.TableOfContents) != 4 || len(rules.Glossary) != 1 || len(rules.Credits.Roles) != 1 {
+			t.Errorf("Parse in %s returned %d introduction paragraphs, %d contents, %d glossary items and %d credits roles",
+				language, len(rules.Introduction), len(rules.TableOfContents), len(rules.Glossary), len(rules.Credits.Roles))
+		}
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	// "Introduction" is also French and "Credits" also German, so only the
+	// glossary header tells the language.
+	blocks := []block{{text: "Introduction"}, {text: "Glossaire"}, {text: "Credits"}}
+	if language := detectLanguage(blocks); language != French {
+		t.Errorf("detectLanguage = %s, expected %s", language, French)
+	}
+
+	if language := detectLanguage([]block{{text: "Introduction"}}); language != English {
+		t.Errorf("detectLanguage without headers = %s, expected %s", language, English)
+	}
+}
+
+func TestAlign(t *testing.T) {
+	english := Rules{Rules: []Section{{ID: "100."}, {ID: "100.1."}, {ID: "100.1a"}}}
+	translated := Rules{Rules: []Section{{ID: "100."}, {ID: "100.1a"}, {ID: "100.1b"}}}
+
+	var ids []string
+	for _, section := range Align(english, translated) {
+		ids = append(ids, section.ID)
+	}
+	if expected := []string{"100.", "100.1.", "100.1a", "100.1b"}; !slices.Equal(ids, expected) {
+		t.Errorf("Align = %v, expected %v", ids, expected)
+	}
+
+	if missing := MissingTranslations(english, translated); !slices.Equal(missing, []string{"100.1."}) {
+		t.Errorf("MissingTranslations = %v, expected [100.1.]", missing)
+	}
+}
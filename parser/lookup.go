@@ -0,0 +1,83 @@
+package parser
+
+import "strings"
+
+// normalizeID returns the ID of a section in the form used by the parser, the
+// trailing period of parts, chapters and rules may be left out from id.
+func normalizeID(id string) string {
+	n, err := ParseRuleNumber(id)
+	if err != nil {
+		return strings.TrimSpace(id)
+	}
+
+	return n.String()
+}
+
+func (r Rules) index(id string) int {
+	for i, section := range r.Rules {
+		if section.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Section returns the section with the given ID.
+func (r Rules) Section(id string) (Section, bool) {
+	i := r.index(normalizeID(id))
+	if i < 0 {
+		return Section{}, false
+	}
+
+	return r.Rules[i], true
+}
+
+// Children returns the sections directly below the section with the given ID,
+// e.g. the rules of a chapter or the subrules of a rule.
+func (r Rules) Children(id string) ([]Section, bool) {
+	i := r.index(normalizeID(id))
+	if i < 0 {
+		return nil, false
+	}
+
+	n, err := ParseRuleNumber(r.Rules[i].ID)
+	if err != nil {
+		return nil, true
+	}
+
+	var out []Section
+	for _, section := range r.Rules[i+1:] {
+		child, err := ParseRuleNumber(section.ID)
+		if err != nil || !n.IsAncestorOf(child) {
+			break
+		}
+
+		if parent, _ := child.Parent(); parent == n {
+			out = append(out, section)
+		}
+	}
+
+	return out, true
+}
+
+// GlossaryItem returns the glossary item matching the term, the term may be
+// either the ID of the item or any of its key parts.
+func (r Rules) GlossaryItem(term string) (GlossaryItem, bool) {
+	id := glossaryID(term)
+	for _, item := range r.Glossary {
+		if item.ID == id {
+			return item, true
+		}
+	}
+
+	for _, item := range r.Glossary {
+		for _, part := range item.KeyParts {
+			if glossaryID(part) == id {
+				return item, true
+			}
+		}
+	}
+
+	return GlossaryItem{}, false
+}
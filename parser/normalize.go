@@ -26,26 +26,42 @@ func normalize(r io.Reader) (string, error) {
 	return unicodeReplacer.Replace(string(text)), nil
 }
 
-func splitSections(text string) []string {
+// block is a paragraph of the source file, lines holds the position of the
+// start of each of its lines.
+type block struct {
+	text  string
+	lines []position
+}
+
+func (b block) pos() position {
+	return b.lines[0]
+}
+
+func splitSections(text string) []block {
 	var (
-		sections []string
+		sections []block
 		section  []string
+		lines    []position
 	)
-	for _, line := range strings.Split(text, "\n") {
+	for i, line := range strings.Split(text, "\n") {
+		pos := position{i + 1, len(line) - len(strings.TrimLeft(line, " \t")) + 1}
+
 		line = strings.TrimSpace(line)
 		if line == "" {
 			if len(section) > 0 {
-				sections = append(sections, strings.Join(section, "\n"))
+				sections = append(sections, block{strings.Join(section, "\n"), lines})
 				section = section[:0]
+				lines = nil
 			}
 
 			continue
 		}
 
 		section = append(section, line)
+		lines = append(lines, pos)
 	}
 	if len(section) > 0 {
-		sections = append(sections, strings.Join(section, "\n"))
+		sections = append(sections, block{strings.Join(section, "\n"), lines})
 	}
 
 	return sections
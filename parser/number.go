@@ -0,0 +1,231 @@
+package parser
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RuleNumber is the number of a part, chapter, rule or subrule, e.g. "7.",
+// "702.", "702.19." and "702.19b". Chapter is the full three digit number of
+// the chapter, Chapter is 0 for parts, Rule is 0 for chapters and Letter is
+// empty for everything but subrules.
+type RuleNumber struct {
+	Part    int
+	Chapter int
+	Rule    int
+	Letter  string
+}
+
+var ruleNumberRegexp = regexp.MustCompile(`^(\d)(?:\.?|(\d{2})(?:\.?|\.(\d+)(?:\.?|([a-z]+)\.?)))$`)
+
+// ParseRuleNumber parses the number of a section. The trailing period of
+// parts, chapters and rules may be left out, and is allowed after subrules as
+// some versions of the rules have them.
+func ParseRuleNumber(s string) (RuleNumber, error) {
+	matches := ruleNumberRegexp.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if matches == nil {
+		return RuleNumber{}, fmt.Errorf("invalid rule number %q", s)
+	}
+
+	var n RuleNumber
+	n.Part, _ = strconv.Atoi(matches[1])
+	if matches[2] != "" {
+		n.Chapter, _ = strconv.Atoi(matches[1] + matches[2])
+	}
+	if matches[3] != "" {
+		n.Rule, _ = strconv.Atoi(matches[3])
+		if n.Rule == 0 {
+			return RuleNumber{}, fmt.Errorf("invalid rule number %q", s)
+		}
+	}
+	n.Letter = matches[4]
+
+	return n, nil
+}
+
+// String returns the number in the form used as the ID of sections.
+func (n RuleNumber) String() string {
+	switch {
+	case n.Chapter == 0:
+		return fmt.Sprintf("%d.", n.Part)
+	case n.Rule == 0:
+		return fmt.Sprintf("%d.", n.Chapter)
+	case n.Letter == "":
+		return fmt.Sprintf("%d.%d.", n.Chapter, n.Rule)
+	default:
+		return fmt.Sprintf("%d.%d%s", n.Chapter, n.Rule, n.Letter)
+	}
+}
+
+func (n RuleNumber) Type() SectionType {
+	switch {
+	case n.Chapter == 0:
+		return Part
+	case n.Rule == 0:
+		return Chapter
+	case n.Letter == "":
+		return Rule
+	default:
+		return SubRule
+	}
+}
+
+// compareLetters orders the letters of subrules, after "z" comes "aa".
+func compareLetters(a, b string) int {
+	return cmp.Or(cmp.Compare(len(a), len(b)), strings.Compare(a, b))
+}
+
+// Compare returns -1, 0 or 1 depending on whether n comes before, is the same
+// as or comes after other in the rules.
+func (n RuleNumber) Compare(other RuleNumber) int {
+	return cmp.Or(
+		cmp.Compare(n.Part, other.Part),
+		cmp.Compare(n.Chapter, other.Chapter),
+		cmp.Compare(n.Rule, other.Rule),
+		compareLetters(n.Letter, other.Letter),
+	)
+}
+
+// Parent returns the number of the section directly above the section of the
+// number, ok is false for parts.
+func (n RuleNumber) Parent() (parent RuleNumber, ok bool) {
+	switch n.Type() {
+	case SubRule:
+		return RuleNumber{n.Part, n.Chapter, n.Rule, ""}, true
+	case Rule:
+		return RuleNumber{n.Part, n.Chapter, 0, ""}, true
+	case Chapter:
+		return RuleNumber{n.Part, 0, 0, ""}, true
+	}
+
+	return RuleNumber{}, false
+}
+
+// IsAncestorOf reports whether the section of other is below the section of n.
+func (n RuleNumber) IsAncestorOf(other RuleNumber) bool {
+	for {
+		var ok bool
+		other, ok = other.Parent()
+		if !ok {
+			return false
+		}
+
+		if other == n {
+			return true
+		}
+	}
+}
+
+// nextLetter returns the letter of the subrule after the subrule with the
+// letter. The letters "l" and "o" are skipped by the rules to avoid confusing
+// them with "1" and "0".
+func nextLetter(letter string) string {
+	b := []byte(letter)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] != 'z' {
+			b[i]++
+			if b[i] == 'l' || b[i] == 'o' {
+				b[i]++
+			}
+
+			return string(b)
+		}
+
+		b[i] = 'a'
+	}
+
+	return "a" + string(b)
+}
+
+var letterRegexp = regexp.MustCompile(`^[a-z]+$`)
+
+// rangeEnd returns the end of a range written as only the part which differs
+// from the start of the range, e.g. "f" in "601.2a–f".
+func rangeEnd(start RuleNumber, s string) (RuleNumber, error) {
+	end := start
+
+	switch start.Type() {
+	case SubRule:
+		if !letterRegexp.MatchString(s) {
+			return RuleNumber{}, fmt.Errorf("invalid letter %q", s)
+		}
+
+		end.Letter = s
+	case Rule:
+		rule, err := strconv.Atoi(strings.TrimSuffix(s, "."))
+		if err != nil {
+			return RuleNumber{}, err
+		}
+
+		end.Rule = rule
+	default:
+		return RuleNumber{}, fmt.Errorf("invalid end of range %q", s)
+	}
+
+	return end, nil
+}
+
+// ExpandRuleRange returns the numbers of the rules in a range such as
+// "601.2a–f", "601.2a-601.2f" or "702.19–21". A single number is returned as
+// is.
+func ExpandRuleRange(s string) ([]RuleNumber, error) {
+	s = strings.ReplaceAll(s, "–", "-")
+
+	startText, endText, isRange := strings.Cut(s, "-")
+
+	start, err := ParseRuleNumber(startText)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isRange {
+		return []RuleNumber{start}, nil
+	}
+
+	end, err := ParseRuleNumber(endText)
+	if err != nil || end.Type() != start.Type() {
+		end, err = rangeEnd(start, strings.TrimSpace(endText))
+		if err != nil {
+			return nil, fmt.Errorf("invalid end of range %q", s)
+		}
+	}
+
+	if start.Type() != end.Type() || start.Compare(end) > 0 {
+		return nil, fmt.Errorf("invalid range %q", s)
+	}
+
+	// The letters skipped by nextLetter would step past the end of the range.
+	if strings.ContainsAny(start.Letter+end.Letter, "lo") {
+		return nil, fmt.Errorf("invalid range %q, subrules skip the letters l and o", s)
+	}
+
+	out := []RuleNumber{start}
+	for n := start; n.Compare(end) < 0; {
+		switch n.Type() {
+		case SubRule:
+			if n.Chapter != end.Chapter || n.Rule != end.Rule {
+				return nil, fmt.Errorf("range %q spans multiple rules", s)
+			}
+
+			n.Letter = nextLetter(n.Letter)
+		case Rule:
+			if n.Chapter != end.Chapter {
+				return nil, fmt.Errorf("range %q spans multiple chapters", s)
+			}
+
+			n.Rule++
+		case Chapter:
+			n.Chapter++
+			n.Part = n.Chapter / 100
+		default:
+			n.Part++
+		}
+
+		out = append(out, n)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseRuleNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		typ      SectionType
+	}{
+		{"7.", "7.", Part},
+		{"7", "7.", Part},
+		{"702.", "702.", Chapter},
+		{"702", "702.", Chapter},
+		{"702.19.", "702.19.", Rule},
+		{"702.19", "702.19.", Rule},
+		{"702.19b", "702.19b", SubRule},
+		{"702.19B", "702.19b", SubRule},
+		{"118.1d.", "118.1d", SubRule},
+	}
+
+	for _, test := range tests {
+		n, err := ParseRuleNumber(test.input)
+		if err != nil {
+			t.Errorf("ParseRuleNumber(%q) returned an error: %v", test.input, err)
+			continue
+		}
+
+		if n.String() != test.expected || n.Type() != test.typ {
+			t.Errorf("ParseRuleNumber(%q) = %s %s, expected %s %s", test.input, n, n.Type(), test.expected, test.typ)
+		}
+	}
+
+	for _, input := range []string{"", "70", "7022.", "702.0", "702.19b1", "702.x"} {
+		if _, err := ParseRuleNumber(input); err == nil {
+			t.Errorf("ParseRuleNumber(%q) expected an error", input)
+		}
+	}
+}
+
+func TestRuleNumberCompare(t *testing.T) {
+	ordered := []string{"1.", "100.", "100.1.", "100.1a", "100.2.", "702.", "702.9.", "702.10.", "702.19z", "702.19aa", "8."}
+
+	for i := range ordered[1:] {
+		a, _ := ParseRuleNumber(ordered[i])
+		b, _ := ParseRuleNumber(ordered[i+1])
+		if a.Compare(b) >= 0 || b.Compare(a) <= 0 {
+			t.Errorf("Expected %s to come before %s", a, b)
+		}
+	}
+}
+
+func TestRuleNumberParent(t *testing.T) {
+	chain := []string{"702.19b", "702.19.", "702.", "7."}
+
+	for i := range chain[1:] {
+		n, _ := ParseRuleNumber(chain[i])
+		parent, ok := n.Parent()
+		if !ok || parent.String() != chain[i+1] {
+			t.Errorf("Parent of %s = %s, expected %s", n, parent, chain[i+1])
+		}
+
+		for _, ancestor := range chain[i+1:] {
+			a, _ := ParseRuleNumber(ancestor)
+			if !a.IsAncestorOf(n) || n.IsAncestorOf(a) {
+				t.Errorf("Expected %s to be an ancestor of %s", a, n)
+			}
+		}
+	}
+
+	part, _ := ParseRuleNumber("7.")
+	if _, ok := part.Parent(); ok {
+		t.Error("Expected a part to have no parent.")
+	}
+
+	other, _ := ParseRuleNumber("702.1.")
+	rule, _ := ParseRuleNumber("702.19a")
+	if other.IsAncestorOf(rule) {
+		t.Errorf("Expected %s not to be an ancestor of %s", other, rule)
+	}
+}
+
+func TestExpandRuleRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"601.2a", []string{"601.2a"}},
+		{"601.2a–f", []string{"601.2a", "601.2b", "601.2c", "601.2d", "601.2e", "601.2f"}},
+		{"704.5k-704.5n", []string{"704.5k", "704.5m", "704.5n"}},
+		{"702.19z–aa", []string{"702.19z", "702.19aa"}},
+		{"702.19–21", []string{"702.19.", "702.20.", "702.21."}},
+	}
+
+	for _, test := range tests {
+		numbers, err := ExpandRuleRange(test.input)
+		if err != nil {
+			t.Errorf("ExpandRuleRange(%q) returned an error: %v", test.input, err)
+			continue
+		}
+
+		var actual []string
+		for _, n := range numbers {
+			actual = append(actual, n.String())
+		}
+
+		if !slices.Equal(actual, test.expected) {
+			t.Errorf("ExpandRuleRange(%q) = %v, expected %v", test.input, actual, test.expected)
+		}
+	}
+
+	for _, input := range []string{"601.2f–a", "601.2a–702.1b", "601.2a–", "601.2k–l", "704.5o-p"} {
+		if _, err := ExpandRuleRange(input); err == nil {
+			t.Errorf("ExpandRuleRange(%q) expected an error", input)
+		}
+	}
+}
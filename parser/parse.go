@@ -1,43 +1,78 @@
 package parser
 
 import (
+	"fmt"
 	"io"
 	"time"
 )
 
 type Rules struct {
-	EffectiveDate time.Time
-	Rules         []Section
-	Glossary      []GlossaryItem
-	Credits       []string
+	EffectiveDate   time.Time       `json:"effectiveDate" doc:"Date from which the rules are in effect."`
+	Language        Language        `json:"language" doc:"Language of the rules as an ISO 639-1 code."`
+	Introduction    []string        `json:"introduction" doc:"Paragraphs of the introduction."`
+	TableOfContents []ContentsEntry `json:"tableOfContents" doc:"Entries of the table of contents in document order."`
+	Rules           []Section       `json:"rules" doc:"Parts, chapters, rules and subrules in document order."`
+	Glossary        []GlossaryItem  `json:"glossary" doc:"Items of the glossary in document order."`
+	Keywords        []Keyword       `json:"keywords" doc:"Keyword actions and keyword abilities in document order."`
+	Credits         Credits         `json:"credits" doc:"Credits section at the end of the rules."`
 }
 
-func Parse(r io.Reader) (Rules, error) {
+// ParseOptions changes how Parse handles problems in the rules.
+type ParseOptions struct {
+	// Lenient makes parsing return everything which could be parsed even when
+	// errors were found, as long as at least some rules were parsed. The errors
+	// are then only reported as diagnostics.
+	Lenient bool
+	// Language is the language of the rules, it is detected from the section
+	// headers when empty.
+	Language Language
+}
+
+// Parse parses the rules from the .txt file of the comprehensive rules. The
+// problems found are returned as diagnostics, the error joins the diagnostics
+// with error severity.
+func Parse(r io.Reader) (Rules, []Diagnostic, error) {
+	return ParseWithOptions(r, ParseOptions{})
+}
+
+// ParseWithOptions is Parse with options.
+func ParseWithOptions(r io.Reader, opts ParseOptions) (Rules, []Diagnostic, error) {
 	normalized, err := normalize(r)
 	if err != nil {
-		return Rules{}, err
+		return Rules{}, nil, err
 	}
 
 	sections := splitSections(normalized)
-	parsed, err := parseSections(sections)
-	if err != nil {
-		return Rules{}, err
-	}
 
-	rules, err := parseRules(parsed.rules)
-	if err != nil {
-		return Rules{}, err
+	language := opts.Language
+	if language == "" {
+		language = detectLanguage(sections)
 	}
 
-	glossary, err := parseGlossary(parsed.glossary)
-	if err != nil {
-		return Rules{}, err
+	l, ok := localizations[language]
+	if !ok {
+		return Rules{}, nil, fmt.Errorf("unsupported language %q", language)
 	}
 
-	credits, err := parseCredits(parsed.credits)
-	if err != nil {
-		return Rules{}, err
+	parsed, ds := parseSections(sections, l)
+
+	introduction := parseIntroduction(parsed.introduction)
+	contents, contentsPositions := parseContents(parsed.contents)
+
+	rules, rulesDiagnostics := parseRules(parsed.rules, l)
+	ds = append(ds, rulesDiagnostics...)
+	ds = append(ds, checkContents(contents, contentsPositions, rules)...)
+
+	glossary, glossaryDiagnostics := parseGlossary(parsed.glossary, rules)
+	ds = append(ds, glossaryDiagnostics...)
+
+	keywords := parseKeywords(rules, glossary)
+	credits := parseCredits(parsed.credits)
+
+	err = diagnosticsErr(ds)
+	if err != nil && (!opts.Lenient || len(rules) == 0) {
+		return Rules{}, ds, err
 	}
 
-	return Rules{parsed.effectiveDate, rules, glossary, credits}, nil
+	return Rules{parsed.effectiveDate, language, introduction, contents, rules, glossary, keywords, credits}, ds, nil
 }
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseLenient(t *testing.T) {
+	// The rule with an invalid number and the glossary item without a body
+	// are errors which lenient parsing skips.
+	input := strings.NewReplacer(
+		"100.1a A", "100.0. A",
+		"Ability\nText on an object that explains what that object does.\n", "Ability\nText on an object.\n\nActivate\n",
+	).Replace(testRules)
+
+	strict, _, err := Parse(strings.NewReader(input))
+	if err == nil || len(strict.Rules) != 0 {
+		t.Errorf("Parse returned %d rules and %v, expected an error", len(strict.Rules), err)
+	}
+
+	rules, ds, err := ParseWithOptions(strings.NewReader(input), ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("lenient Parse returned %v", err)
+	}
+
+	var codes []DiagnosticCode
+	for _, d := range ds {
+		codes = append(codes, d.Code)
+	}
+	if expected := []DiagnosticCode{CodeInvalidNumber, CodeGlossaryItemNoBody}; !slices.Equal(codes, expected) {
+		t.Errorf("lenient Parse returned diagnostics %v, expected %v", codes, expected)
+	}
+
+	var ids []string
+	for _, section := range rules.Rules {
+		ids = append(ids, section.ID)
+	}
+	if expected := []string{"1.", "100.", "100.1."}; !slices.Equal(ids, expected) {
+		t.Errorf("lenient Parse returned rules %v, expected %v", ids, expected)
+	}
+
+	if len(rules.Glossary) != 1 || rules.Glossary[0].ID != "ability" {
+		t.Errorf("lenient Parse returned glossary %v, expected only ability", rules.Glossary)
+	}
+
+	if rules.EffectiveDate.Format("2006-01-02") != "2026-01-16" || len(rules.Credits.Roles) != 1 {
+		t.Errorf("lenient Parse returned effective date %s and credits %v", rules.EffectiveDate, rules.Credits)
+	}
+
+	// Without any rules there is nothing to return even when lenient.
+	start := strings.Index(testRules, "\n1. Game Concepts\n\n")
+	end := strings.Index(testRules, "\nGlossary\n\nAbility")
+	input = testRules[:start] + testRules[end:]
+	if _, _, err := ParseWithOptions(strings.NewReader(input), ParseOptions{Lenient: true}); err == nil {
+		t.Error("lenient Parse of rules without any rules expected an error")
+	}
+}
@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DocumentKind is a rules document published by Wizards of the Coast.
+type DocumentKind string
+
+const (
+	CR  DocumentKind = "CR"
+	MTR DocumentKind = "MTR"
+	IPG DocumentKind = "IPG"
+)
+
+// Documents are all of the documents.
+var Documents = []DocumentKind{CR, MTR, IPG}
+
+// DocumentURLs are the pages the documents are published on.
+var DocumentURLs = map[DocumentKind]string{
+	CR:  "https://magic.wizards.com/en/rules",
+	MTR: "https://wpn.wizards.com/en/rules-documents",
+	IPG: "https://wpn.wizards.com/en/rules-documents",
+}
+
+// SiteURL is the URL of the site the documents are rendered on.
+const SiteURL = "https://rulesraker.com"
+
+// DocumentPages are the pages of the site the documents are rendered to, the
+// comprehensive rules are rendered to the index page.
+var DocumentPages = map[DocumentKind]string{
+	CR:  "",
+	MTR: "mtr.html",
+	IPG: "ipg.html",
+}
+
+// documentNames are the names the documents are referred to by in the text of
+// the documents.
+var documentNames = map[string]DocumentKind{
+	"Comprehensive Rules":                   CR,
+	"Magic: The Gathering Tournament Rules": MTR,
+	"Magic Tournament Rules":                MTR,
+	"Infraction Procedure Guide":            IPG,
+}
+
+// documentNamesPattern returns a pattern matching any of the document names,
+// longest first so that the full name is matched.
+func documentNamesPattern() string {
+	names := slices.SortedFunc(maps.Keys(documentNames), func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+
+	for i, name := range names {
+		names[i] = regexp.QuoteMeta(name)
+	}
+
+	return strings.Join(names, "|")
+}
+
+// Reference is a reference to a section of a rules document, ID is empty for
+// references to the whole document. IDs of the comprehensive rules are rule
+// numbers and IDs of the other documents are section numbers.
+type Reference struct {
+	Document DocumentKind `json:"document"`
+	ID       string       `json:"id,omitempty"`
+}
+
+// String returns the reference in the form "MTR 2.4" or "CR 702.19b".
+func (r Reference) String() string {
+	if r.ID == "" {
+		return string(r.Document)
+	}
+
+	return string(r.Document) + " " + r.ID
+}
+
+// URL returns the page of the site the document of the reference is rendered
+// to, references to sections link to the anchor of the section on the page.
+func (r Reference) URL() string {
+	url := SiteURL + "/" + DocumentPages[r.Document]
+	if r.ID != "" {
+		url += "#" + r.ID
+	}
+
+	return url
+}
+
+func normalizeReferenceID(document DocumentKind, id string) (string, error) {
+	if id == "" {
+		return "", nil
+	}
+
+	if document == CR {
+		n, err := ParseRuleNumber(id)
+		return n.String(), err
+	}
+
+	n, err := ParseSectionNumber(id)
+	return n.String(), err
+}
+
+// ParseReference parses a reference written as by Reference.String, e.g.
+// "MTR 2.4", "cr 702.19b" or "IPG".
+func ParseReference(s string) (Reference, error) {
+	document, id, _ := strings.Cut(strings.TrimSpace(s), " ")
+
+	kind := DocumentKind(strings.ToUpper(document))
+	if _, ok := DocumentURLs[kind]; !ok {
+		return Reference{}, fmt.Errorf("invalid reference %q: unknown document %q", s, document)
+	}
+
+	id, err := normalizeReferenceID(kind, strings.TrimSpace(id))
+	if err != nil {
+		return Reference{}, fmt.Errorf("invalid reference %q: %w", s, err)
+	}
+
+	return Reference{kind, id}, nil
+}
+
+// ReferenceSpan is a reference found in text, Start and End are the byte
+// offsets of the reference in the text.
+type ReferenceSpan struct {
+	Start int
+	End   int
+	Reference
+}
+
+var referenceRegexp = regexp.MustCompile(
+	`\b(?P<document>CR|MTR|IPG) (?:[Ss]ection |[Rr]ule )?(?P<id>\d+(?:\.\d+[a-z]?)?)\b` +
+		`|\b(?:[Rr]ules?|Comprehensive Rules) (?P<rule>\d{3}(?:\.\d+[a-z]?)?)\b` +
+		`|\b[Ss]ections? (?P<section>\d+\.\d+)\b` +
+		`|(?P<name>` + documentNamesPattern() + `)`,
+)
+
+// FindReferences returns the references to other documents and to sections of
+// the same document in the text of a document of the kind. Other documents
+// are referred to by their abbreviation or name, e.g. "MTR 2.4" or
+// "Infraction Procedure Guide", rules of the comprehensive rules also as
+// "rule 702.19b" and sections of the same document as "section 2.4".
+// References to rules in the comprehensive rules themselves are left to the
+// Tokenizer.
+func FindReferences(from DocumentKind, s string) []ReferenceSpan {
+	var out []ReferenceSpan
+
+	for _, match := range referenceRegexp.FindAllStringSubmatchIndex(s, -1) {
+		group := func(name string) string {
+			i := 2 * referenceRegexp.SubexpIndex(name)
+			if match[i] < 0 {
+				return ""
+			}
+
+			return s[match[i]:match[i+1]]
+		}
+
+		var (
+			ref Reference
+			id  string
+		)
+		switch {
+		case group("document") != "":
+			ref.Document, id = DocumentKind(group("document")), group("id")
+		case group("rule") != "":
+			ref.Document, id = CR, group("rule")
+		case group("section") != "":
+			ref.Document, id = from, group("section")
+		default:
+			ref.Document = documentNames[group("name")]
+		}
+
+		if from == CR && (ref.Document == CR || group("section") != "") {
+			continue
+		}
+		if from != CR && ref.Document == from && id == "" {
+			continue
+		}
+
+		var err error
+		ref.ID, err = normalizeReferenceID(ref.Document, id)
+		if err != nil {
+			continue
+		}
+
+		out = append(out, ReferenceSpan{match[0], match[1], ref})
+	}
+
+	return out
+}
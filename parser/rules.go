@@ -1,11 +1,6 @@
 package parser
 
-import (
-	"errors"
-	"fmt"
-	"regexp"
-	"strings"
-)
+import "strings"
 
 type SectionType string
 
@@ -16,88 +11,62 @@ const (
 	SubRule SectionType = "SubRule"
 )
 
-var (
-	sectionNumberRegexp = regexp.MustCompile(`^\d\.$`)
-	chapterNumberRegexp = regexp.MustCompile(`^\d{3}\.$`)
-	ruleNumberRegexp    = regexp.MustCompile(`^\d{3}\.\d+\.$`)
-	subRuleNumberRegexp = regexp.MustCompile(`^\d{3}\.\d+\w+$`)
-)
-
-func parsePartType(number string) (SectionType, error) {
-	if sectionNumberRegexp.MatchString(number) {
-		return Part, nil
-	}
-
-	if chapterNumberRegexp.MatchString(number) {
-		return Chapter, nil
-	}
-
-	if ruleNumberRegexp.MatchString(number) {
-		return Rule, nil
-	}
-
-	if subRuleNumberRegexp.MatchString(number) {
-		return SubRule, nil
-	}
-
-	return "", fmt.Errorf("invalid part number %q", number)
-}
-
 type Section struct {
-	ID       string
-	Number   string
-	Type     SectionType
-	Body     []string
-	Examples []string `json:",omitempty"`
+	ID       string      `json:"id" doc:"Identifier of the section, e.g. \"702.19b\"."`
+	Number   string      `json:"number" doc:"Number of the section as written in the rules."`
+	Type     SectionType `json:"type" doc:"Level of the section in the rules hierarchy."`
+	Body     []string    `json:"body" doc:"Paragraphs of the section, for parts and chapters the only paragraph is the name."`
+	Examples []string    `json:"examples" doc:"Examples of the section without the \"Example:\" prefix."`
+	Cards    []string    `json:"cards" doc:"Names of the cards mentioned in the body and examples in the order they are first mentioned."`
 }
 
-var numberRegexp = regexp.MustCompile(`^(\d+)(\.((\d+)(\w+)?))?\.?`)
-
-func parseNumber(rule string) (string, string, error) {
-	matches := numberRegexp.FindStringSubmatch(rule)
-	if matches == nil {
-		return "", "", fmt.Errorf("failed to parse rule id from %q", rule)
-	}
-
-	ruleWithoutNumber := rule[len(matches[0]):]
-
-	major := matches[1]
-	minor := matches[4]
-	letter := matches[5]
+// isNumberRune reports whether the rune can be a part of a rule number.
+func isNumberRune(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r == '.'
+}
 
-	if minor == "" && letter == "" {
-		return ruleWithoutNumber, fmt.Sprintf("%v.", major), nil
+// parseNumber splits the number from the start of the line, ok is false if
+// the line does not start with a digit. The number is left for
+// ParseRuleNumber to validate, some versions of the rules leave out the space
+// after it, e.g. "901.4.All".
+func parseNumber(line string) (rest, number string, ok bool) {
+	if line == "" || line[0] < '0' || line[0] > '9' {
+		return line, "", false
 	}
 
-	if letter == "" {
-		return ruleWithoutNumber, fmt.Sprintf("%v.%v.", major, minor), nil
+	i := strings.IndexFunc(line, func(r rune) bool { return !isNumberRune(r) })
+	if i < 0 {
+		return "", line, true
 	}
 
-	return ruleWithoutNumber, fmt.Sprintf("%v.%v%v", major, minor, letter), nil
+	return line[i:], line[:i], true
 }
 
-func parseRules(rules []string) ([]Section, error) {
+func parseRules(rules []block, l localization) ([]Section, diagnostics) {
 	out := make([]Section, 0, len(rules))
 
-	var err error
-	for _, rule := range rules {
-		ruleWithoutNumber, number, errParseNumber := parseNumber(rule)
-		if errParseNumber != nil {
+	var ds diagnostics
+	for _, b := range rules {
+		rule := b.text
+
+		ruleWithoutNumber, rawNumber, ok := parseNumber(rule)
+		if !ok {
 			// This section doesn't start with a rule ID - treat as continuation of previous rule.
 			if len(out) == 0 {
-				err = errors.Join(err, errParseNumber)
+				ds.add(SeverityError, CodeInvalidNumber, b.pos(), "", "failed to parse rule id from %q", rule)
 				continue
 			}
 
 			prev := &out[len(out)-1]
+			ds.add(SeverityWarning, CodeContinuation, b.pos(), prev.ID, "paragraph without a rule number continues the previous rule")
+
 			lines := strings.Split(rule, "\n")
 			for _, line := range lines {
 				line = strings.TrimSpace(line)
 				if line == "" {
 					continue
 				}
-				if strings.HasPrefix(line, "Example:") {
-					example := strings.TrimSpace(strings.TrimPrefix(line, "Example:"))
+				if example, ok := l.example(line); ok {
 					prev.Examples = append(prev.Examples, example)
 				} else if len(prev.Examples) > 0 {
 					// If we already have examples, this is likely example continuation text.
@@ -109,6 +78,15 @@ func parseRules(rules []string) ([]Section, error) {
 			continue
 		}
 
+		ruleNumber, err := ParseRuleNumber(rawNumber)
+		if err != nil {
+			ds.add(SeverityError, CodeInvalidNumber, b.pos(), "", "%v", err)
+			continue
+		}
+
+		number := ruleNumber.String()
+		partType := ruleNumber.Type()
+
 		lines := strings.Split(ruleWithoutNumber, "\n")
 
 		var (
@@ -117,10 +95,10 @@ func parseRules(rules []string) ([]Section, error) {
 		)
 
 		inBody := true
-		for _, line := range lines {
+		for i, line := range lines {
 			line = strings.TrimSpace(line)
 
-			isExample := strings.HasPrefix(line, "Example:")
+			example, isExample := l.example(line)
 			if isExample {
 				inBody = false
 			}
@@ -129,28 +107,21 @@ func parseRules(rules []string) ([]Section, error) {
 				body = append(body, line)
 			} else {
 				if !isExample {
-					err = errors.Join(err, fmt.Errorf("rule body text after examples %q", rule))
+					ds.add(SeverityError, CodeBodyAfterExamples, b.lines[i], number, "rule body text after examples %q", line)
 					continue
 				}
 
-				example := strings.TrimSpace(strings.TrimPrefix(line, "Example:"))
 				examples = append(examples, example)
 			}
 		}
 
-		partType, errParsePartType := parsePartType(number)
-		if err != nil {
-			err = errors.Join(err, errParsePartType)
-			continue
-		}
-
 		if len(body) == 0 {
-			err = errors.Join(err, fmt.Errorf("rule with no body text %q", rule))
+			ds.add(SeverityError, CodeEmptyBody, b.pos(), number, "rule with no body text %q", rule)
 			continue
 		}
 
 		if (partType == Part || partType == Chapter) && len(body) != 1 {
-			err = errors.Join(err, fmt.Errorf("rule of type %s must have exactly one body element: %q", partType, rule))
+			ds.add(SeverityError, CodeInvalidHeading, b.pos(), number, "rule of type %s must have exactly one body element: %q", partType, rule)
 			continue
 		}
 
@@ -163,5 +134,5 @@ func parseRules(rules []string) ([]Section, error) {
 		})
 	}
 
-	return out, err
+	return out, ds
 }
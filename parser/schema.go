@@ -0,0 +1,278 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FormatVersion is the version of the JSON format of Rules. It has to be
+// incremented whenever the shape of the JSON changes.
+const FormatVersion = 8
+
+// schemaIDFormat is the format of the identifier of the JSON Schema of each
+// version of the format.
+const schemaIDFormat = "https://rulesraker.com/schema/MagicCompRules.v%d.schema.json"
+
+func (r Rules) MarshalJSON() ([]byte, error) {
+	type rules Rules
+	return json.Marshal(struct {
+		FormatVersion int `json:"formatVersion"`
+		rules
+	}{FormatVersion, rules(r)})
+}
+
+func (r *Rules) UnmarshalJSON(data []byte) error {
+	type rules Rules
+	var versioned struct {
+		FormatVersion int `json:"formatVersion"`
+		*rules
+	}
+	versioned.rules = (*rules)(r)
+
+	err := json.Unmarshal(data, &versioned)
+	if err != nil {
+		return err
+	}
+
+	if versioned.FormatVersion != FormatVersion {
+		return fmt.Errorf("unsupported format version %d, expected %d", versioned.FormatVersion, FormatVersion)
+	}
+
+	return nil
+}
+
+func (s Section) MarshalJSON() ([]byte, error) {
+	type section Section
+	if s.Examples == nil {
+		s.Examples = []string{}
+	}
+	if s.Cards == nil {
+		s.Cards = []string{}
+	}
+
+	return json.Marshal(section(s))
+}
+
+// schemaEnums lists the allowed values of the string types which are enums.
+var schemaEnums = map[reflect.Type][]any{
+	reflect.TypeFor[SectionType](): {Part, Chapter, Rule, SubRule},
+	reflect.TypeFor[KeywordKind](): {KeywordAction, KeywordAbility},
+	reflect.TypeFor[Language]():    {English, French, German, Italian, Spanish, Portuguese, Japanese},
+}
+
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	if enum, ok := schemaEnums[t]; ok {
+		return map[string]any{"type": "string", "enum": enum}
+	}
+
+	if t == reflect.TypeFor[time.Time]() {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int:
+		return map[string]any{"type": "integer"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil
+			g.defs[t.Name()] = g.object(t, nil)
+		}
+
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	}
+
+	panic(fmt.Sprintf("no JSON schema for type %s", t))
+}
+
+func (g *schemaGenerator) object(t reflect.Type, extra map[string]any) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+
+	for name, schema := range extra {
+		properties[name] = schema
+		required = append(required, name)
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		schema := g.schema(field.Type)
+		if doc := field.Tag.Get("doc"); doc != "" {
+			schema["description"] = doc
+		}
+
+		name := jsonFieldName(field)
+		properties[name] = schema
+
+		if !strings.Contains(field.Tag.Get("json"), ",omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	slices.Sort(required)
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// JSONSchema returns the JSON Schema describing the JSON format of Rules.
+func JSONSchema() map[string]any {
+	g := schemaGenerator{defs: make(map[string]any)}
+
+	schema := g.object(reflect.TypeFor[Rules](), map[string]any{
+		"formatVersion": map[string]any{
+			"description": "Version of the format, incremented on every change to the shape of the document.",
+			"const":       FormatVersion,
+		},
+	})
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$id"] = fmt.Sprintf(schemaIDFormat, FormatVersion)
+	schema["title"] = "Magic: The Gathering Comprehensive Rules"
+	schema["$defs"] = g.defs
+
+	return schema
+}
+
+// ValidationError is a single violation of the schema, Path is a JSON pointer
+// to the invalid value.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+type schemaValidator struct {
+	root map[string]any
+	errs []error
+}
+
+func (v *schemaValidator) fail(path, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{path, fmt.Sprintf(format, args...)})
+}
+
+func (v *schemaValidator) validate(path string, schema map[string]any, value any) {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		v.validate(path, v.root["$defs"].(map[string]any)[name].(map[string]any), value)
+	}
+
+	if constant, ok := schema["const"]; ok && fmt.Sprint(constant) != fmt.Sprint(value) {
+		v.fail(path, "must be %v", constant)
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(value) }) {
+		v.fail(path, "must be one of %v", enum)
+	}
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			v.fail(path, "must be an object")
+			return
+		}
+
+		properties, _ := schema["properties"].(map[string]any)
+		for _, name := range schema["required"].([]string) {
+			if _, ok := object[name]; !ok {
+				v.fail(path, "missing required property %q", name)
+			}
+		}
+
+		for name, value := range object {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				v.fail(path, "unknown property %q", name)
+				continue
+			}
+
+			v.validate(path+"/"+name, property, value)
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			v.fail(path, "must be an array")
+			return
+		}
+
+		for i, item := range array {
+			v.validate(fmt.Sprintf("%s/%d", path, i), schema["items"].(map[string]any), item)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			v.fail(path, "must be a string")
+			return
+		}
+
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				v.fail(path, "must be a RFC 3339 date-time")
+			}
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			v.fail(path, "must be an integer")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(path, "must be a boolean")
+		}
+	}
+}
+
+// ValidateJSON validates the JSON document against the JSON Schema of the
+// current format. All of the violations are returned joined together.
+func ValidateJSON(data []byte) error {
+	var value any
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+
+	schema := JSONSchema()
+	v := schemaValidator{root: schema}
+	v.validate("", schema, value)
+
+	return errors.Join(v.errs...)
+}
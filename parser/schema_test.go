@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestJSONSchema fails whenever the shape of the JSON changes without the
+// committed schema being regenerated.
+func TestJSONSchema(t *testing.T) {
+	expected, err := os.ReadFile(filepath.Join("..", "data", "MagicCompRules.schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+
+	var actual bytes.Buffer
+	enc := json.NewEncoder(&actual)
+	enc.SetIndent("", "  ")
+
+	err = enc.Encode(JSONSchema())
+	if err != nil {
+		t.Fatalf("Failed to encode schema: %v", err)
+	}
+
+	if !bytes.Equal(bytes.ReplaceAll(expected, []byte("\r\n"), []byte("\n")), actual.Bytes()) {
+		t.Fatal("The JSON format has changed, increment FormatVersion if the change is " +
+			"incompatible and regenerate the schema with `rulesraker parse`.")
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	fp, err := os.Open(filepath.Join("..", "data", "MagicCompRules.txt"))
+	if err != nil {
+		t.Fatalf("Failed to open rules: %v", err)
+	}
+	defer fp.Close()
+
+	rules, _, err := Parse(fp)
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		t.Fatalf("Failed to marshal rules: %v", err)
+	}
+
+	err = ValidateJSON(data)
+	if err != nil {
+		t.Fatalf("Marshaled rules do not match the schema: %v", err)
+	}
+
+	var decoded Rules
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal rules: %v", err)
+	}
+
+	if !reflect.DeepEqual(rules.Glossary, decoded.Glossary) || len(rules.Rules) != len(decoded.Rules) {
+		t.Error("Rules changed in a round trip through JSON.")
+	}
+
+	err = json.Unmarshal([]byte(`{"formatVersion": 0}`), &decoded)
+	if err == nil {
+		t.Error("Expected an error for an unsupported format version.")
+	}
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// SearchResult is a single section or glossary item matching a search query,
+// exactly one of Section and GlossaryItem is set.
+type SearchResult struct {
+	Score        int           `json:"score"`
+	Section      *Section      `json:"section,omitempty"`
+	GlossaryItem *GlossaryItem `json:"glossaryItem,omitempty"`
+}
+
+// Scores given for the different kinds of matches. A match of the whole query
+// is worth more than matches of its individual words and matches in names are
+// worth more than matches in the body text.
+const (
+	scoreWord        = 1
+	scorePhrase      = 5
+	scoreTitlePhrase = 20
+	scoreExactTitle  = 50
+)
+
+func scoreText(text, phrase string, words []string) (int, bool) {
+	text = strings.ToLower(text)
+
+	score := 0
+	for _, word := range words {
+		count := strings.Count(text, word)
+		if count == 0 {
+			return 0, false
+		}
+
+		score += count * scoreWord
+	}
+
+	score += strings.Count(text, phrase) * scorePhrase
+
+	return score, true
+}
+
+func scoreTitle(title, phrase string) int {
+	title = strings.ToLower(strings.TrimSpace(title))
+	if title == phrase {
+		return scoreExactTitle
+	}
+
+	if strings.Contains(title, phrase) {
+		return scoreTitlePhrase
+	}
+
+	return 0
+}
+
+// Search finds the sections and glossary items containing all of the words of
+// the query, ordered by relevance. At most limit results are returned, or all
+// of them if limit is not positive.
+func (r Rules) Search(query string, limit int) []SearchResult {
+	phrase := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	words := strings.Fields(phrase)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var out []SearchResult
+
+	for i := range r.Rules {
+		section := &r.Rules[i]
+
+		text := strings.Join(append(append([]string{section.ID}, section.Body...), section.Examples...), "\n")
+		score, ok := scoreText(text, phrase, words)
+		if !ok {
+			continue
+		}
+
+		// Parts, chapters and rules such as 702.2. have a name as their body.
+		if section.Type != SubRule {
+			score += scoreTitle(section.Body[0], phrase)
+		}
+
+		if section.ID == phrase || section.ID == phrase+"." {
+			score += scoreExactTitle
+		}
+
+		out = append(out, SearchResult{Score: score, Section: section})
+	}
+
+	for i := range r.Glossary {
+		item := &r.Glossary[i]
+
+		score, ok := scoreText(item.KeyText+"\n"+item.Body, phrase, words)
+		if !ok {
+			continue
+		}
+
+		titleScore := 0
+		for _, part := range item.KeyParts {
+			titleScore = max(titleScore, scoreTitle(part, phrase))
+		}
+
+		out = append(out, SearchResult{Score: score + titleScore, GlossaryItem: item})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Score > out[j].Score
+	})
+
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+
+	return out
+}
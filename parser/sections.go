@@ -1,57 +1,69 @@
 package parser
 
 import (
-	"errors"
-	"fmt"
-	"regexp"
 	"strings"
 	"time"
 )
 
 type parsedRules struct {
 	effectiveDate time.Time
-	rules         []string
-	glossary      []string
-	credits       []string
+	introduction  []block
+	contents      []block
+	rules         []block
+	glossary      []block
+	credits       []block
 }
 
-var effectiveDateRegexp = regexp.MustCompile(`\w+ \d+, \d+`)
-
 type parseState int
 
 const (
 	parseStateStart parseState = iota
+	parseStateIntroduction
+	parseStateContents
 	parseStateRules
 	parseStateGlossary
 	parseStateCredits
 )
 
-func parseSections(sections []string) (parsedRules, error) {
+func parseSections(blocks []block, l localization) (parsedRules, diagnostics) {
 	var (
 		state         parseState
 		effectiveDate time.Time
-		rules         []string
-		glossary      []string
-		credits       []string
+		introduction  []block
+		contents      []block
+		rules         []block
+		glossary      []block
+		credits       []block
 	)
-	for _, section := range sections {
-		section = strings.TrimSpace(section)
+	for _, b := range blocks {
+		section := strings.TrimSpace(b.text)
 
 		if effectiveDate.IsZero() {
-			match := effectiveDateRegexp.FindString(section)
-			effectiveDate, _ = time.Parse("January 2, 2006", match)
+			effectiveDate, _ = l.parseDate(section)
 		}
 
+		if state == parseStateStart && isHeader(l.introduction, section) {
+			state = parseStateIntroduction
+			continue
+		}
+		if (state == parseStateStart || state == parseStateIntroduction) && isHeader(l.contents, section) {
+			state = parseStateContents
+			continue
+		}
 		// The last item of the table of contents is "Credits", after which the rules start.
-		if state == parseStateStart && section == "Credits" {
+		if state < parseStateRules && isHeader(l.credits, section) {
+			if state == parseStateContents {
+				contents = append(contents, b)
+			}
+
 			state = parseStateRules
 			continue
 		}
-		if state == parseStateRules && section == "Glossary" {
+		if state == parseStateRules && isHeader(l.glossary, section) {
 			state = parseStateGlossary
 			continue
 		}
-		if state == parseStateGlossary && section == "Credits" {
+		if state == parseStateGlossary && isHeader(l.credits, section) {
 			state = parseStateCredits
 			continue
 		}
@@ -61,29 +73,33 @@ func parseSections(sections []string) (parsedRules, error) {
 		}
 
 		switch state {
+		case parseStateIntroduction:
+			introduction = append(introduction, b)
+		case parseStateContents:
+			contents = append(contents, b)
 		case parseStateRules:
-			rules = append(rules, section)
+			rules = append(rules, b)
 		case parseStateGlossary:
-			glossary = append(glossary, section)
+			glossary = append(glossary, b)
 		case parseStateCredits:
-			credits = append(credits, section)
+			credits = append(credits, b)
 		}
 	}
 
-	var err error
+	var ds diagnostics
 
 	if effectiveDate.IsZero() {
-		err = errors.Join(fmt.Errorf("failed to parse effective date from the file"))
+		ds.add(SeverityError, CodeNoEffectiveDate, position{}, "", "failed to parse effective date from the file")
 	}
 	if len(rules) == 0 {
-		err = errors.Join(err, fmt.Errorf("failed to parse any rules from the file"))
+		ds.add(SeverityError, CodeNoRules, position{}, "", "failed to parse any rules from the file")
 	}
 	if len(glossary) == 0 {
-		err = errors.Join(err, fmt.Errorf("failed to parse any glossary items from the file"))
+		ds.add(SeverityError, CodeNoGlossary, position{}, "", "failed to parse any glossary items from the file")
 	}
 	if len(credits) == 0 {
-		err = errors.Join(err, fmt.Errorf("failed to parse any credits from the file"))
+		ds.add(SeverityError, CodeNoCredits, position{}, "", "failed to parse any credits from the file")
 	}
 
-	return parsedRules{effectiveDate, rules, glossary, credits}, err
+	return parsedRules{effectiveDate, introduction, contents, rules, glossary, credits}, ds
 }
@@ -0,0 +1,70 @@
+"use strict";
+
+// Card names in the rules are previewed with their image from Scryfall when
+// hovered.
+
+var cardImages = {};
+
+function getCardImage(name) {
+  if (!cardImages[name]) {
+    var url =
+      "https://api.scryfall.com/cards/named?exact=" + encodeURIComponent(name);
+
+    cardImages[name] = fetch(url)
+      .then(function (resp) {
+        if (!resp.ok) throw new Error("card " + name + " not found");
+        return resp.json();
+      })
+      .then(function (card) {
+        var face = card.image_uris ? card : card.card_faces[0];
+        return face.image_uris.normal;
+      });
+  }
+
+  return cardImages[name];
+}
+
+function cardsMain() {
+  var content = document.querySelector("#content");
+  var preview = document.createElement("img");
+  preview.className = "card-preview";
+  preview.hidden = true;
+  document.body.appendChild(preview);
+
+  var current = null;
+
+  content.addEventListener("mouseover", function (ev) {
+    var el = ev.target.closest(".card-name");
+    if (!el || el === current) return;
+
+    current = el;
+    var name = el.dataset.card;
+    getCardImage(name)
+      .then(function (src) {
+        if (current !== el) return;
+
+        var rect = el.getBoundingClientRect();
+        preview.src = src;
+        preview.alt = name;
+        preview.style.left = rect.left + "px";
+        preview.style.top =
+          (rect.top > window.innerHeight / 2
+            ? rect.top - 348
+            : rect.bottom + 8) + "px";
+        preview.hidden = false;
+      })
+      .catch(function (err) {
+        console.log(err);
+      });
+  });
+
+  content.addEventListener("mouseout", function (ev) {
+    var el = ev.target.closest(".card-name");
+    if (!el || el.contains(ev.relatedTarget)) return;
+
+    current = null;
+    preview.hidden = true;
+  });
+}
+
+window.addEventListener("load", cardsMain);
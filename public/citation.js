@@ -0,0 +1,71 @@
+"use strict";
+
+// The citation of a rule is copied to the clipboard when its copy button is
+// clicked. The citation is built from the rule on the page in the same layout
+// as the official .txt file, only the effective date and the URL of the rules
+// are given by the page.
+
+// citationLine returns the text of the element with the symbols written out as
+// in the rules text.
+function citationLine(el) {
+  var out = "";
+  el.childNodes.forEach(function (node) {
+    if (node.nodeType === Node.TEXT_NODE) {
+      out += node.textContent;
+    } else if (node.nodeName.toLowerCase() === "img") {
+      out += node.alt;
+    } else if (!node.classList.contains("copy-citation")) {
+      out += citationLine(node);
+    }
+  });
+
+  return out;
+}
+
+// citation returns the citation of the rule whose first paragraph is rule,
+// including the paragraphs and examples which follow it.
+function citation(content, rule) {
+  var lines = [citationLine(rule).trim()];
+  for (
+    var el = rule.nextElementSibling;
+    el && el.nodeName.toLowerCase() === "p";
+    el = el.nextElementSibling
+  ) {
+    lines.push(citationLine(el).trim());
+  }
+
+  var ref = rule.querySelector(".number").getAttribute("href").slice(1);
+  return (
+    lines.join("\n") +
+    "\n\n— Magic: The Gathering Comprehensive Rules " +
+    ref.replace(/\.$/, "") +
+    ", effective " +
+    content.dataset.effectiveDate +
+    ". " +
+    content.dataset.rulesUrl +
+    "\n"
+  );
+}
+
+function citationMain() {
+  var content = document.querySelector("#content");
+
+  content.addEventListener("click", function (ev) {
+    var button = ev.target.closest(".copy-citation");
+    if (!button) return;
+
+    navigator.clipboard
+      .writeText(citation(content, button.closest("p")))
+      .then(function () {
+        button.classList.add("copied");
+        setTimeout(function () {
+          button.classList.remove("copied");
+        }, 2000);
+      })
+      .catch(function (err) {
+        console.log(err);
+      });
+  });
+}
+
+window.addEventListener("load", citationMain);